@@ -1,6 +1,7 @@
 package cedexis
 
 import (
+	"context"
 	"fmt"
 	"strings"
 )
@@ -181,8 +182,16 @@ func (c *Client) NewAlert(name string, t AlertType, platform int,
 
 // CreateAlert creates new alerts.
 func (c *Client) CreateAlert(alert *Alert) (*Alert, error) {
+	return c.CreateAlertWithContext(context.Background(), alert)
+}
+
+// CreateAlertWithContext is CreateAlert with a context for cancellation and deadlines.
+func (c *Client) CreateAlertWithContext(ctx context.Context, alert *Alert) (_ *Alert, err error) {
+	ctx, span := c.startSpan(ctx, "CreateAlert")
+	defer func() { endSpan(span, err) }()
+
 	out := Alert{}
-	err := c.postJSON(baseURL+alertsConfigPath, &alert, &out)
+	err = c.postJSON(ctx, c.baseURL+alertsConfigPath, &alert, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -191,17 +200,39 @@ func (c *Client) CreateAlert(alert *Alert) (*Alert, error) {
 
 // UpdateAlert creates new alerts.
 func (c *Client) UpdateAlert(alert *Alert) (*Alert, error) {
-	err := c.putJSON(baseURL+alertsConfigPath+fmt.Sprintf("/%d", *alert.ID), &alert, nil)
+	return c.UpdateAlertWithContext(context.Background(), alert)
+}
+
+// UpdateAlertWithContext is UpdateAlert with a context for cancellation and deadlines.
+func (c *Client) UpdateAlertWithContext(ctx context.Context, alert *Alert) (_ *Alert, err error) {
+	ctx, span := c.startSpan(ctx, "UpdateAlert")
+	defer func() { endSpan(span, err) }()
+
+	err = c.putJSON(ctx, c.baseURL+alertsConfigPath+fmt.Sprintf("/%d", *alert.ID), &alert, nil)
 	if err != nil {
 		return nil, err
 	}
-	return c.GetAlert(*alert.ID)
+
+	// Nothing changed to read back in a dry run
+	if c.changeLog != nil {
+		return alert, nil
+	}
+
+	return c.GetAlertWithContext(ctx, *alert.ID)
 }
 
 // GetAlert gets an alert.
 func (c *Client) GetAlert(id int) (*Alert, error) {
+	return c.GetAlertWithContext(context.Background(), id)
+}
+
+// GetAlertWithContext is GetAlert with a context for cancellation and deadlines.
+func (c *Client) GetAlertWithContext(ctx context.Context, id int) (_ *Alert, err error) {
+	ctx, span := c.startSpan(ctx, "GetAlert")
+	defer func() { endSpan(span, err) }()
+
 	result := Alert{}
-	err := c.getJSON(baseURL+alertsConfigPath+fmt.Sprintf("/%d", id), &result)
+	err = c.getJSON(ctx, c.baseURL+alertsConfigPath+fmt.Sprintf("/%d", id), &result)
 	if err != nil {
 		return nil, err
 	}
@@ -210,13 +241,29 @@ func (c *Client) GetAlert(id int) (*Alert, error) {
 
 // DeleteAlert deletes an alert.
 func (c *Client) DeleteAlert(id int) error {
-	return c.delete(baseURL + alertsConfigPath + fmt.Sprintf("/%d", id))
+	return c.DeleteAlertWithContext(context.Background(), id)
+}
+
+// DeleteAlertWithContext is DeleteAlert with a context for cancellation and deadlines.
+func (c *Client) DeleteAlertWithContext(ctx context.Context, id int) (err error) {
+	ctx, span := c.startSpan(ctx, "DeleteAlert")
+	defer func() { endSpan(span, err) }()
+
+	return c.delete(ctx, c.baseURL+alertsConfigPath+fmt.Sprintf("/%d", id))
 }
 
 // GetAlerts returns all configured alerts.
 func (c *Client) GetAlerts() ([]*Alert, error) {
+	return c.GetAlertsWithContext(context.Background())
+}
+
+// GetAlertsWithContext is GetAlerts with a context for cancellation and deadlines.
+func (c *Client) GetAlertsWithContext(ctx context.Context) (_ []*Alert, err error) {
+	ctx, span := c.startSpan(ctx, "GetAlerts")
+	defer func() { endSpan(span, err) }()
+
 	var resp []*Alert
-	err := c.getJSON(baseURL+alertsConfigPath, &resp)
+	err = c.getJSON(ctx, c.baseURL+alertsConfigPath, &resp)
 
 	if err != nil {
 		return nil, err
@@ -227,7 +274,15 @@ func (c *Client) GetAlerts() ([]*Alert, error) {
 
 // GetAlertByName returns an alert by name.
 func (c *Client) GetAlertByName(name string) (*Alert, error) {
-	alerts, err := c.GetAlerts()
+	return c.GetAlertByNameWithContext(context.Background(), name)
+}
+
+// GetAlertByNameWithContext is GetAlertByName with a context for cancellation and deadlines.
+func (c *Client) GetAlertByNameWithContext(ctx context.Context, name string) (_ *Alert, err error) {
+	ctx, span := c.startSpan(ctx, "GetAlertByName")
+	defer func() { endSpan(span, err) }()
+
+	alerts, err := c.GetAlertsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
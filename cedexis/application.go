@@ -1,6 +1,7 @@
 package cedexis
 
 import (
+	"context"
 	"fmt"
 	"sort"
 )
@@ -319,55 +320,77 @@ func (a *Application) DiffersFrom(other *Application) bool {
 
 // GetApplications gets all applications.
 func (c *Client) GetApplications() ([]*Application, error) {
-	var resp []*Application
+	return c.GetApplicationsWithContext(context.Background())
+}
 
-	if len(c.appCache) == 0 {
-		err := c.getJSON(baseURL+appsConfigPath, &resp)
+// GetApplicationsWithContext is GetApplications with a context for cancellation and deadlines.
+func (c *Client) GetApplicationsWithContext(ctx context.Context) (_ []*Application, err error) {
+	ctx, span := c.startSpan(ctx, "GetApplications")
+	defer func() { endSpan(span, err) }()
 
-		if err != nil {
-			return nil, err
-		}
+	var resp []*Application
 
-		c.appCache = map[int]*Application{}
-		for _, a := range resp {
-			c.appCache[*a.ID] = a
+	if cached, ok := c.cache.list(CacheApplications); ok {
+		resp = make([]*Application, 0, len(cached))
+		for _, a := range cached {
+			resp = append(resp, a.(*Application))
 		}
 
-	} else {
-		resp = make([]*Application, 0, len(c.appCache))
-		for _, a := range c.appCache {
-			resp = append(resp, a)
-		}
+		return resp, nil
+	}
+
+	err = c.getJSON(ctx, c.baseURL+appsConfigPath, &resp)
+	if err != nil {
+		return nil, err
 	}
 
+	ids := make([]int, 0, len(resp))
+	values := make([]interface{}, 0, len(resp))
+	for _, a := range resp {
+		ids = append(ids, *a.ID)
+		values = append(values, a)
+	}
+	c.cache.putAll(CacheApplications, ids, values)
+
 	return resp, nil
 }
 
 // GetApplication gets an alert.
 func (c *Client) GetApplication(id int) (*Application, error) {
-	var result *Application
+	return c.GetApplicationWithContext(context.Background(), id)
+}
 
-	result = c.appCache[id]
-	if result != nil {
-		return result, nil
+// GetApplicationWithContext is GetApplication with a context for cancellation and deadlines.
+func (c *Client) GetApplicationWithContext(ctx context.Context, id int) (_ *Application, err error) {
+	ctx, span := c.startSpan(ctx, "GetApplication")
+	defer func() { endSpan(span, err) }()
+
+	if cached, ok := c.cache.get(CacheApplications, id); ok {
+		return cached.(*Application), nil
 	}
 
-	result = &Application{}
-	err := c.getJSON(baseURL+appsConfigPath+fmt.Sprintf("/%d", id), result)
+	result := &Application{}
+	err = c.getJSON(ctx, c.baseURL+appsConfigPath+fmt.Sprintf("/%d", id), result)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(c.appCache) > 0 {
-		c.appCache[*result.ID] = result
-	}
+	c.cache.put(CacheApplications, *result.ID, result)
 
 	return result, err
 }
 
 // GetApplicationByName gets an application by name.
 func (c *Client) GetApplicationByName(name string) (*Application, error) {
-	apps, err := c.GetApplications()
+	return c.GetApplicationByNameWithContext(context.Background(), name)
+}
+
+// GetApplicationByNameWithContext is GetApplicationByName with a context for cancellation and deadlines.
+func (c *Client) GetApplicationByNameWithContext(ctx context.Context, name string) (_ *Application, err error) {
+	ctx, span := c.startSpan(ctx, "GetApplicationByName")
+	defer func() { endSpan(span, err) }()
+
+	apps, err := c.GetApplicationsWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -383,40 +406,69 @@ func (c *Client) GetApplicationByName(name string) (*Application, error) {
 
 // CreateApplication creates an application
 func (c *Client) CreateApplication(app *Application) (*Application, error) {
+	return c.CreateApplicationWithContext(context.Background(), app)
+}
+
+// CreateApplicationWithContext is CreateApplication with a context for cancellation and deadlines.
+func (c *Client) CreateApplicationWithContext(ctx context.Context, app *Application) (_ *Application, err error) {
+	ctx, span := c.startSpan(ctx, "CreateApplication")
+	defer func() { endSpan(span, err) }()
+
 	out := &Application{}
-	err := c.postJSON(baseURL+appsConfigPath, app, out)
+	err = c.postJSON(ctx, c.baseURL+appsConfigPath, app, out)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(c.appCache) > 0 {
-		c.appCache[*out.ID] = out
-	}
+	c.cacheChange(CacheApplications, *out.ID, out)
 
 	return out, nil
 }
 
 // UpdateApplication updates an app.
 func (c *Client) UpdateApplication(app *Application) (*Application, error) {
-	err := c.putJSON(baseURL+appsConfigPath+fmt.Sprintf("/%d", *app.ID), app, nil)
+	return c.UpdateApplicationWithContext(context.Background(), app)
+}
+
+// UpdateApplicationWithContext is UpdateApplication with a context for cancellation and deadlines.
+func (c *Client) UpdateApplicationWithContext(ctx context.Context, app *Application) (_ *Application, err error) {
+	ctx, span := c.startSpan(ctx, "UpdateApplication")
+	defer func() { endSpan(span, err) }()
+
+	err = c.putJSON(ctx, c.baseURL+appsConfigPath+fmt.Sprintf("/%d", *app.ID), app, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	out := &Application{}
-	err = c.getJSON(baseURL+appsConfigPath+fmt.Sprintf("/%d", *app.ID), out)
-	if err != nil && len(c.appCache) > 0 {
-		c.appCache[*out.ID] = out
+	// Nothing changed to read back in a dry run
+	out := app
+	if c.changeLog == nil {
+		out = &Application{}
+		err = c.getJSON(ctx, c.baseURL+appsConfigPath+fmt.Sprintf("/%d", *app.ID), out)
+		if err != nil {
+			c.cache.invalidate(CacheApplications, *app.ID)
+			return nil, err
+		}
 	}
 
+	c.cacheChange(CacheApplications, *out.ID, out)
+
 	return out, nil
 }
 
 // DeleteApplication deletes an app.
 func (c *Client) DeleteApplication(id int) error {
-	err := c.delete(baseURL + appsConfigPath + fmt.Sprintf("/%d", id))
-	if err != nil {
-		delete(c.appCache, id)
+	return c.DeleteApplicationWithContext(context.Background(), id)
+}
+
+// DeleteApplicationWithContext is DeleteApplication with a context for cancellation and deadlines.
+func (c *Client) DeleteApplicationWithContext(ctx context.Context, id int) (err error) {
+	ctx, span := c.startSpan(ctx, "DeleteApplication")
+	defer func() { endSpan(span, err) }()
+
+	err = c.delete(ctx, c.baseURL+appsConfigPath+fmt.Sprintf("/%d", id))
+	if err == nil {
+		c.cache.remove(CacheApplications, id)
 	}
 	return err
 }
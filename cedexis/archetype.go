@@ -0,0 +1,272 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Archetype is a community platform a private platform can be based on (see
+// NewPublicCloudPrivatePlatform)
+type Archetype struct {
+	ID   int
+	Name string
+
+	// Provider is the short provider name, e.g. 'aws', or empty if not recognized
+	Provider string
+
+	// Region is the provider's region code, e.g. 'eu-west-1', or empty if the name has none
+	Region string
+
+	// Category is nil if the platform is not categorized
+	Category     *PlatformCategory
+	CategoryName string
+}
+
+// archetypeProviders maps short provider names to the words identifying them in platform names
+var archetypeProviders = map[string][]string{
+	"aws":          {"aws", "amazon"},
+	"azure":        {"azure", "microsoft"},
+	"gcp":          {"gcp", "google"},
+	"alibaba":      {"alibaba", "aliyun"},
+	"oracle":       {"oracle", "oci"},
+	"ibm":          {"ibm", "softlayer"},
+	"digitalocean": {"digitalocean"},
+	"linode":       {"linode"},
+	"ovh":          {"ovh"},
+}
+
+var regionCode = regexp.MustCompile(`\b[a-z]{2,}(-[a-z]+)+-?[0-9]+[a-z]?\b`)
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ArchetypeQuery selects archetypes.  Empty fields match any archetype.
+type ArchetypeQuery struct {
+	// Text is matched loosely against provider, region and name, e.g. 'aws eu-west-1'
+	Text string
+
+	// Provider is a short provider name or one of its aliases, e.g. 'aws' or 'amazon'
+	Provider string
+
+	// Region is a region code, compared ignoring case and punctuation
+	Region string
+
+	Category *PlatformCategory
+}
+
+// AmbiguousArchetypeError is returned when a lookup matches more than one archetype equally well
+type AmbiguousArchetypeError struct {
+	Query   string
+	Matches []*Archetype
+}
+
+func (e *AmbiguousArchetypeError) Error() string {
+	names := make([]string, 0, len(e.Matches))
+	for _, a := range e.Matches {
+		names = append(names, fmt.Sprintf("'%s'", a.Name))
+	}
+	return fmt.Sprintf("Archetype '%s' is ambiguous, it matches %s", e.Query, strings.Join(names, ", "))
+}
+
+// ArchetypeCatalogue is a searchable list of archetypes
+type ArchetypeCatalogue struct {
+	archetypes []*Archetype
+}
+
+// GetArchetypeCatalogue gets the community platforms as an ArchetypeCatalogue
+func (c *Client) GetArchetypeCatalogue() (*ArchetypeCatalogue, error) {
+	return c.GetArchetypeCatalogueWithContext(context.Background())
+}
+
+// GetArchetypeCatalogueWithContext is GetArchetypeCatalogue with a context for cancellation and deadlines.
+func (c *Client) GetArchetypeCatalogueWithContext(ctx context.Context) (_ *ArchetypeCatalogue, err error) {
+	ctx, span := c.startSpan(ctx, "GetArchetypeCatalogue")
+	defer func() { endSpan(span, err) }()
+
+	platforms, err := c.GetPlatformsWithContext(ctx, PlatformsTypeCommunity)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := c.GetProviderCategoriesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewArchetypeCatalogue(platforms, categories), nil
+}
+
+// NewArchetypeCatalogue builds a catalogue of platforms, naming categories from categories (see
+// GetProviderCategories) where the platforms don't
+func NewArchetypeCatalogue(platforms []*PlatformInfo, categories []*NameID) *ArchetypeCatalogue {
+	categoryNames := map[PlatformCategory]string{}
+	for _, c := range categories {
+		if c.ID != nil && c.Name != nil {
+			categoryNames[*c.ID] = *c.Name
+		}
+	}
+
+	archetypes := make([]*Archetype, 0, len(platforms))
+	for _, p := range platforms {
+		if p.ID == nil || p.Name == nil {
+			continue
+		}
+
+		a := &Archetype{ID: *p.ID, Name: *p.Name}
+		lower := strings.ToLower(a.Name)
+		a.Provider = providerOf(lower)
+		a.Region = regionCode.FindString(lower)
+
+		if p.Category != nil && p.Category.ID != nil {
+			category := *p.Category.ID
+			a.Category = &category
+			a.CategoryName = categoryNames[category]
+			if p.Category.Name != nil {
+				a.CategoryName = *p.Category.Name
+			}
+		}
+
+		archetypes = append(archetypes, a)
+	}
+
+	sort.Slice(archetypes, func(i, j int) bool { return archetypes[i].Name < archetypes[j].Name })
+
+	return &ArchetypeCatalogue{archetypes: archetypes}
+}
+
+// All lists the archetypes, ordered by name
+func (c *ArchetypeCatalogue) All() []*Archetype {
+	return append([]*Archetype(nil), c.archetypes...)
+}
+
+// Find lists the archetypes matching q, best matches first
+func (c *ArchetypeCatalogue) Find(q ArchetypeQuery) []*Archetype {
+	type scored struct {
+		a     *Archetype
+		score int
+	}
+
+	var matches []scored
+	for _, a := range c.archetypes {
+		if score, ok := a.match(q); ok {
+			matches = append(matches, scored{a, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	result := make([]*Archetype, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.a)
+	}
+	return result
+}
+
+// Lookup finds the single archetype best matching q.  An exact name or ID in q.Text is always
+// chosen, otherwise it is an AmbiguousArchetypeError if several match equally well.
+func (c *ArchetypeCatalogue) Lookup(q ArchetypeQuery) (*Archetype, error) {
+	var best []*Archetype
+	bestScore := 0
+	for _, a := range c.archetypes {
+		score, ok := a.match(q)
+		if !ok {
+			continue
+		}
+
+		if strings.EqualFold(a.Name, q.Text) || strconv.Itoa(a.ID) == q.Text {
+			return a, nil
+		}
+
+		switch {
+		case score > bestScore:
+			best, bestScore = []*Archetype{a}, score
+		case score == bestScore:
+			best = append(best, a)
+		}
+	}
+
+	switch len(best) {
+	case 0:
+		return nil, fmt.Errorf("No archetype matches '%s'", q)
+	case 1:
+		return best[0], nil
+	default:
+		return nil, &AmbiguousArchetypeError{Query: q.String(), Matches: best}
+	}
+}
+
+func (q ArchetypeQuery) String() string {
+	parts := []string{}
+	for _, s := range []string{q.Provider, q.Region, q.Text} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if q.Category != nil {
+		parts = append(parts, fmt.Sprintf("category %d", int(*q.Category)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// match reports whether a matches q, and how well: region codes count most, then providers, then
+// other words of the name
+func (a *Archetype) match(q ArchetypeQuery) (int, bool) {
+	if q.Category != nil && (a.Category == nil || *a.Category != *q.Category) {
+		return 0, false
+	}
+	if q.Provider != "" && providerOf(strings.ToLower(q.Provider)) != a.Provider {
+		return 0, false
+	}
+	if q.Region != "" && (a.Region == "" || squash(q.Region) != squash(a.Region)) {
+		return 0, false
+	}
+
+	if strconv.Itoa(a.ID) == q.Text {
+		return 0, true
+	}
+
+	text := squash(a.Name + " " + a.CategoryName)
+	score := 0
+	for _, word := range strings.Fields(strings.ToLower(q.Text)) {
+		switch {
+		case a.Region != "" && squash(word) == squash(a.Region):
+			score += 3
+		case providerOf(word) != "":
+			if providerOf(word) != a.Provider {
+				return 0, false
+			}
+			score += 2
+		case strings.Contains(text, squash(word)):
+			score++
+		default:
+			return 0, false
+		}
+	}
+
+	return score, true
+}
+
+// providerOf gives the short name of the provider named in s (lower case), or empty if none is
+func providerOf(s string) string {
+	for _, word := range nonWord.Split(s, -1) {
+		for provider, aliases := range archetypeProviders {
+			if word == provider {
+				return provider
+			}
+			for _, alias := range aliases {
+				if word == alias {
+					return provider
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// squash lower-cases s and drops punctuation, so 'EU West 1' and 'eu-west-1' compare equal
+func squash(s string) string {
+	return nonWord.ReplaceAllString(strings.ToLower(s), "")
+}
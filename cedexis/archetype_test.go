@@ -0,0 +1,64 @@
+package cedexis_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestArchetypeCatalogue(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	cloud := cedexis.PlatformCategoryCloudComputing
+	cdn := cedexis.PlatformCategoryDeliveryNetwork
+	add := func(name string, category *cedexis.PlatformCategory) int {
+		p := &cedexis.PlatformInfo{Name: &name}
+		if category != nil {
+			p.Category = &cedexis.NameID{ID: category}
+		}
+		return *srv.AddCommunityPlatform(p).ID
+	}
+	awsIreland := add("Amazon Web Services EC2 - eu-west-1 (Ireland)", &cloud)
+	add("Amazon Web Services EC2 - us-east-1 (Virginia)", &cloud)
+	gcpBelgium := add("Google Cloud Compute - europe-west1 (Belgium)", &cloud)
+	cloudFront := add("Amazon CloudFront", &cdn)
+	add("Uncategorized Platform", nil)
+
+	cat, err := c.GetArchetypeCatalogue()
+	if err != nil {
+		t.Fatalf("GetArchetypeCatalogue failed: %v", err)
+	}
+
+	lookups := map[string]int{
+		"aws eu-west-1":        awsIreland,
+		"amazon EU West 1":     awsIreland,
+		"gcp europe-west1":     gcpBelgium,
+		"belgium":              gcpBelgium,
+		"aws cloudfront":       cloudFront,
+		"Amazon CloudFront":    cloudFront,
+		"aws delivery network": cloudFront,
+	}
+	for query, want := range lookups {
+		a, err := cat.Lookup(cedexis.ArchetypeQuery{Text: query})
+		if err != nil || a.ID != want {
+			t.Errorf("Lookup(%q) got (%+v, %v), want %d", query, a, err, want)
+		}
+	}
+
+	var ambiguous *cedexis.AmbiguousArchetypeError
+	if _, err := cat.Lookup(cedexis.ArchetypeQuery{Text: "aws", Category: &cloud}); !errors.As(err, &ambiguous) || len(ambiguous.Matches) != 2 {
+		t.Errorf("Lookup(aws) got %v, want ambiguity between two regions", err)
+	}
+
+	if _, err := cat.Lookup(cedexis.ArchetypeQuery{Text: "azure westeurope"}); err == nil {
+		t.Errorf("Expected error looking up a missing archetype")
+	}
+
+	if found := cat.Find(cedexis.ArchetypeQuery{Provider: "amazon", Category: &cloud}); len(found) != 2 {
+		t.Errorf("Find(amazon, cloud) got %d archetypes, want 2", len(found))
+	}
+	if found := cat.Find(cedexis.ArchetypeQuery{Region: "EU-WEST-1"}); len(found) != 1 || found[0].Provider != "aws" {
+		t.Errorf("Find(EU-WEST-1) got %v, want the aws archetype", found)
+	}
+}
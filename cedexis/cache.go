@@ -0,0 +1,269 @@
+package cedexis
+
+import (
+	"sync"
+	"time"
+)
+
+// CacheKind identifies a class of resource cached by the client
+type CacheKind int
+
+const (
+	// CacheZones caches DNS zones (and their records)
+	CacheZones CacheKind = iota
+
+	// CachePlatformList caches the list of private platforms
+	CachePlatformList
+
+	// CachePlatforms caches private platform configurations
+	CachePlatforms
+
+	// CacheApplications caches Openmix applications
+	CacheApplications
+
+	// CacheCountries caches the country list
+	CacheCountries
+)
+
+// DefaultCacheTTL is how long cached resources are used before being re-fetched
+const DefaultCacheTTL = 5 * time.Minute
+
+// DefaultCountriesCacheTTL is how long the (rarely changing) country list is cached
+const DefaultCountriesCacheTTL = 24 * time.Hour
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+type cacheSet struct {
+	items map[int]cacheEntry
+
+	// order is the IDs of items in the order they were listed by Cedexis, then put
+	order []int
+
+	// complete indicates items holds every resource of the kind, until listExpires
+	complete    bool
+	listExpires time.Time
+}
+
+// cache holds resources by kind and ID, it is safe for concurrent use.  A nil *cache caches
+// nothing.
+type cache struct {
+	mu   sync.Mutex
+	ttls map[CacheKind]time.Duration
+	sets map[CacheKind]*cacheSet
+}
+
+func newCache(ttls map[CacheKind]time.Duration) *cache {
+	c := &cache{
+		ttls: map[CacheKind]time.Duration{
+			CacheZones:        DefaultCacheTTL,
+			CachePlatformList: DefaultCacheTTL,
+			CachePlatforms:    DefaultCacheTTL,
+			CacheApplications: DefaultCacheTTL,
+			CacheCountries:    DefaultCountriesCacheTTL,
+		},
+		sets: map[CacheKind]*cacheSet{},
+	}
+
+	for k, ttl := range ttls {
+		c.ttls[k] = ttl
+	}
+
+	return c
+}
+
+func (c *cache) set(kind CacheKind) *cacheSet {
+	s := c.sets[kind]
+	if s == nil {
+		s = &cacheSet{items: map[int]cacheEntry{}}
+		c.sets[kind] = s
+	}
+	return s
+}
+
+// delete removes an item, keeping the order of the others
+func (s *cacheSet) delete(id int) {
+	if _, ok := s.items[id]; !ok {
+		return
+	}
+
+	delete(s.items, id)
+	for i, o := range s.order {
+		if o == id {
+			s.order = append(s.order[:i:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (c *cache) expiry(kind CacheKind) time.Time {
+	return time.Now().Add(c.ttls[kind])
+}
+
+// get returns a single cached resource
+func (c *cache) get(kind CacheKind, id int) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.set(kind).items[id]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+// list returns every resource of a kind in the order they were listed, if the full list is cached
+func (c *cache) list(kind CacheKind) ([]interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.set(kind)
+	if !s.complete || time.Now().After(s.listExpires) {
+		return nil, false
+	}
+
+	result := make([]interface{}, 0, len(s.order))
+	for _, id := range s.order {
+		result = append(result, s.items[id].value)
+	}
+
+	return result, true
+}
+
+// putAll replaces the cached resources of a kind with a complete list, values[i] having ID ids[i]
+func (c *cache) putAll(kind CacheKind, ids []int, values []interface{}) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.expiry(kind)
+	s := &cacheSet{items: make(map[int]cacheEntry, len(ids)), complete: true, listExpires: expires}
+	for i, id := range ids {
+		if _, ok := s.items[id]; !ok {
+			s.order = append(s.order, id)
+		}
+		s.items[id] = cacheEntry{value: values[i], expires: expires}
+	}
+	c.sets[kind] = s
+}
+
+// put caches a single resource, the cached list (if any) remains complete
+func (c *cache) put(kind CacheKind, id int, v interface{}) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.set(kind)
+	if _, ok := s.items[id]; !ok {
+		s.order = append(s.order, id)
+	}
+	s.items[id] = cacheEntry{value: v, expires: c.expiry(kind)}
+}
+
+// update replaces a cached resource with the result of fn, if it is cached
+func (c *cache) update(kind CacheKind, id int, fn func(v interface{}) interface{}) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.set(kind)
+	if e, ok := s.items[id]; ok && !time.Now().After(e.expires) {
+		e.value = fn(e.value)
+		s.items[id] = e
+	}
+}
+
+// remove evicts a resource known to have been deleted, the cached list (if any) remains complete
+func (c *cache) remove(kind CacheKind, id int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(kind).delete(id)
+}
+
+// invalidate evicts a resource whose state is unknown, so the list must be re-fetched
+func (c *cache) invalidate(kind CacheKind, id int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.set(kind)
+	s.delete(id)
+	s.complete = false
+}
+
+// invalidateKind evicts every resource of a kind
+func (c *cache) invalidateKind(kind CacheKind) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.sets, kind)
+}
+
+func (c *cache) invalidateAll() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sets = map[CacheKind]*cacheSet{}
+}
+
+// cacheChange caches the result of a change.  A dry run's results are fabricated, so the resource
+// is evicted instead.
+func (c *Client) cacheChange(kind CacheKind, id int, value interface{}) {
+	if c.changeLog != nil {
+		c.cache.invalidate(kind, id)
+		return
+	}
+	c.cache.put(kind, id, value)
+}
+
+// Invalidate evicts one cached resource, so the next read fetches it from Cedexis.  Lists that
+// included the resource are also re-fetched.
+func (c *Client) Invalidate(kind CacheKind, id int) {
+	c.cache.invalidate(kind, id)
+}
+
+// InvalidateKind evicts all cached resources of a kind.
+func (c *Client) InvalidateKind(kind CacheKind) {
+	c.cache.invalidateKind(kind)
+}
+
+// InvalidateAll evicts everything from the client's cache.
+func (c *Client) InvalidateAll() {
+	c.cache.invalidateAll()
+}
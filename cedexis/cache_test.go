@@ -0,0 +1,99 @@
+package cedexis
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCacheList(t *testing.T) {
+	c := newCache(nil)
+
+	if _, ok := c.list(CacheZones); ok {
+		t.Errorf("Empty cache should not have a list")
+	}
+
+	c.putAll(CacheZones, []int{1, 2}, []interface{}{"a", "b"})
+	c.put(CacheZones, 3, "c")
+	if l, ok := c.list(CacheZones); !ok || len(l) != 3 {
+		t.Errorf("Incorrect list after put, got %v (%v), want 3 items", l, ok)
+	}
+
+	c.remove(CacheZones, 1)
+	if l, ok := c.list(CacheZones); !ok || len(l) != 2 {
+		t.Errorf("Incorrect list after remove, got %v (%v), want 2 items", l, ok)
+	}
+
+	c.invalidate(CacheZones, 2)
+	if _, ok := c.list(CacheZones); ok {
+		t.Errorf("List should be incomplete after invalidate")
+	}
+	if v, ok := c.get(CacheZones, 3); !ok || v != "c" {
+		t.Errorf("Incorrect item after invalidate, got %v (%v), want c", v, ok)
+	}
+}
+
+func TestCacheListOrder(t *testing.T) {
+	c := newCache(nil)
+
+	c.putAll(CacheZones, []int{3, 1, 2}, []interface{}{"c", "a", "b"})
+	for i := 0; i < 10; i++ {
+		if l, _ := c.list(CacheZones); !reflect.DeepEqual(l, []interface{}{"c", "a", "b"}) {
+			t.Fatalf("Got list %v, want listed order [c a b]", l)
+		}
+	}
+
+	c.put(CacheZones, 1, "A")
+	c.put(CacheZones, 0, "z")
+	c.remove(CacheZones, 3)
+	if l, _ := c.list(CacheZones); !reflect.DeepEqual(l, []interface{}{"A", "b", "z"}) {
+		t.Errorf("Got list %v after put and remove, want [A b z]", l)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	c := newCache(map[CacheKind]time.Duration{CacheApplications: -time.Second})
+
+	c.putAll(CacheApplications, []int{1}, []interface{}{"a"})
+	if _, ok := c.list(CacheApplications); ok {
+		t.Errorf("Expired list should not be returned")
+	}
+	if _, ok := c.get(CacheApplications, 1); ok {
+		t.Errorf("Expired item should not be returned")
+	}
+
+	c.putAll(CacheZones, []int{1}, []interface{}{"a"})
+	if _, ok := c.get(CacheZones, 1); !ok {
+		t.Errorf("Unexpired item should be returned")
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	var c *cache
+
+	c.put(CacheZones, 1, "a")
+	if _, ok := c.get(CacheZones, 1); ok {
+		t.Errorf("Disabled cache should not return items")
+	}
+	c.invalidateAll()
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	c := newCache(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.put(CachePlatforms, j, i)
+				c.get(CachePlatforms, j)
+				c.list(CachePlatforms)
+				c.invalidate(CachePlatforms, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
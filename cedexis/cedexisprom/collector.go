@@ -0,0 +1,134 @@
+// Package cedexisprom exports metrics about Cedexis API usage to Prometheus.
+//
+//	collector := cedexisprom.NewCollector("myapp")
+//	prometheus.MustRegister(collector)
+//	client := cedexis.NewClientWithOptions(ctx, id, secret, cedexis.WithInstrumentation(collector))
+//
+// A single collector may be shared by many clients.
+package cedexisprom
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a prometheus.Collector that records requests reported by cedexis.Instrumentation
+type Collector struct {
+	requests        *prometheus.CounterVec
+	latency         *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	rateLimitWaits  *prometheus.CounterVec
+	rateLimitWaited *prometheus.CounterVec
+	throttled       *prometheus.CounterVec
+	throttledWaited *prometheus.CounterVec
+}
+
+var _ cedexis.Instrumentation = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector creates a Collector, with metric names prefixed by namespace (if not empty)
+func NewCollector(namespace string) *Collector {
+	labels := []string{"resource", "method"}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "requests_total",
+			Help:      "Cedexis API requests, by resource, method and status class (2xx..5xx, or error).",
+		}, []string{"resource", "method", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Cedexis API requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "retries_total",
+			Help:      "Cedexis API requests retried after a failure, other than 429 Too Many Requests.",
+		}, labels),
+		rateLimitWaits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "rate_limit_waits_total",
+			Help:      "Cedexis API requests delayed by the client rate limiter.",
+		}, labels),
+		rateLimitWaited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "rate_limit_wait_seconds_total",
+			Help:      "Time spent waiting for the client rate limiter.",
+		}, labels),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "throttled_total",
+			Help:      "Cedexis API requests retried after a 429 Too Many Requests response.",
+		}, labels),
+		throttledWaited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cedexis",
+			Name:      "throttled_wait_seconds_total",
+			Help:      "Time spent backing off after 429 Too Many Requests responses.",
+		}, labels),
+	}
+}
+
+// RequestDone implements cedexis.Instrumentation
+func (c *Collector) RequestDone(resource string, method string, status int, latency time.Duration) {
+	c.requests.WithLabelValues(resource, method, statusClass(status)).Inc()
+	c.latency.WithLabelValues(resource, method).Observe(latency.Seconds())
+}
+
+// Retried implements cedexis.Instrumentation
+func (c *Collector) Retried(resource string, method string, status int, delay time.Duration) {
+	if status == http.StatusTooManyRequests {
+		c.throttled.WithLabelValues(resource, method).Inc()
+		c.throttledWaited.WithLabelValues(resource, method).Add(delay.Seconds())
+		return
+	}
+	c.retries.WithLabelValues(resource, method).Inc()
+}
+
+// RateLimited implements cedexis.Instrumentation
+func (c *Collector) RateLimited(resource string, method string, wait time.Duration) {
+	c.rateLimitWaits.WithLabelValues(resource, method).Inc()
+	c.rateLimitWaited.WithLabelValues(resource, method).Add(wait.Seconds())
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.retries.Describe(ch)
+	c.rateLimitWaits.Describe(ch)
+	c.rateLimitWaited.Describe(ch)
+	c.throttled.Describe(ch)
+	c.throttledWaited.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.retries.Collect(ch)
+	c.rateLimitWaits.Collect(ch)
+	c.rateLimitWaited.Collect(ch)
+	c.throttled.Collect(ch)
+	c.throttledWaited.Collect(ch)
+}
+
+// statusClass buckets a status code, e.g. 404 is "4xx"
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "error"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
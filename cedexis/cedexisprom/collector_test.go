@@ -0,0 +1,69 @@
+package cedexisprom_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+	"github.com/ctxkenb/cedexis-golang/cedexis/cedexisprom"
+	"github.com/ctxkenb/cedexis-golang/cedexis/cedexistest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	srv := cedexistest.NewServer()
+	defer srv.Close()
+
+	collector := cedexisprom.NewCollector("test")
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(collector)
+
+	c := srv.Client(context.Background(),
+		cedexis.WithInstrumentation(collector),
+		cedexis.WithRateLimit(50, 1),
+		cedexis.WithRetryPolicy(&cedexis.BackoffRetryPolicy{
+			MaxAttempts:     3,
+			BaseDelay:       time.Millisecond,
+			MaxDelay:        time.Millisecond,
+			RetryableStatus: cedexis.DefaultRetryableStatus,
+		}))
+
+	srv.InjectFault(cedexistest.Fault{Method: "GET", Path: "/meta", Status: 503, Times: 1})
+	srv.InjectFault(cedexistest.Fault{Method: "GET", Path: "/config/authdns.json", Status: 429, Times: 1})
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if _, err := c.GetZones(); err != nil {
+		t.Fatalf("GetZones failed: %v", err)
+	}
+
+	expected := `
+# HELP test_cedexis_requests_total Cedexis API requests, by resource, method and status class (2xx..5xx, or error).
+# TYPE test_cedexis_requests_total counter
+test_cedexis_requests_total{method="GET",resource="ping",status="2xx"} 1
+test_cedexis_requests_total{method="GET",resource="ping",status="5xx"} 1
+test_cedexis_requests_total{method="GET",resource="zone",status="2xx"} 1
+test_cedexis_requests_total{method="GET",resource="zone",status="4xx"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "test_cedexis_requests_total"); err != nil {
+		t.Errorf("Incorrect request counts: %v", err)
+	}
+
+	expected = `
+# HELP test_cedexis_retries_total Cedexis API requests retried after a failure, other than 429 Too Many Requests.
+# TYPE test_cedexis_retries_total counter
+test_cedexis_retries_total{method="GET",resource="ping"} 1
+# HELP test_cedexis_throttled_total Cedexis API requests retried after a 429 Too Many Requests response.
+# TYPE test_cedexis_throttled_total counter
+test_cedexis_throttled_total{method="GET",resource="zone"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "test_cedexis_retries_total", "test_cedexis_throttled_total"); err != nil {
+		t.Errorf("Incorrect retry counts: %v", err)
+	}
+	if n, err := testutil.GatherAndCount(reg, "test_cedexis_rate_limit_waits_total"); err != nil || n == 0 {
+		t.Errorf("Got (%d, %v) rate limit wait series, want some", n, err)
+	}
+}
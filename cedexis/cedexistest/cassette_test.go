@@ -0,0 +1,80 @@
+package cedexistest
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cassettes", "platforms.json")
+
+	// Record against the fake
+	srv := NewServer()
+	srv.AddCommunityPlatform(&cedexis.PlatformInfo{Name: strPtr("Amazon EC2 - eu-west-1")})
+
+	rec, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	client := cedexis.NewClientWithOptions(ctx, "my-client-id", "my-client-secret",
+		cedexis.WithBaseURL(srv.BaseURL()), cedexis.WithTokenURL(srv.TokenURL()),
+		cedexis.WithTransport(rec), cedexis.WithCacheDisabled())
+	created, err := client.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+	recorded, err := client.GetPlatforms(cedexis.PlatformsTypeCommunity)
+	if err != nil {
+		t.Fatalf("GetPlatforms failed: %v", err)
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	srv.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Cassette not written: %v", err)
+	}
+	for _, secret := range []string{"my-client-id", "my-client-secret", Token} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Cassette contains credential %q", secret)
+		}
+	}
+
+	// Replay, with the fake closed
+	rep, err := NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatalf("NewRecorder (replay) failed: %v", err)
+	}
+
+	client = cedexis.NewClientWithOptions(ctx, "other-id", "other-secret",
+		cedexis.WithBaseURL(srv.BaseURL()), cedexis.WithTokenURL(srv.TokenURL()),
+		cedexis.WithTransport(rep), cedexis.WithCacheDisabled(), cedexis.WithRetryPolicy(nil))
+
+	replayed, err := client.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil || *replayed.ID != *created.ID {
+		t.Errorf("Replayed CreatePrivatePlatform got (%v, %v), want ID %d", replayed, err, *created.ID)
+	}
+
+	platforms, err := client.GetPlatforms(cedexis.PlatformsTypeCommunity)
+	if err != nil || len(platforms) != 1 || *platforms[0].Name != *recorded[0].Name {
+		t.Errorf("Replayed GetPlatforms got (%v, %v), want %v", platforms, err, recorded)
+	}
+
+	if _, err := client.GetPlatforms(cedexis.PlatformsTypeSystem); err == nil {
+		t.Errorf("Unrecorded request should fail on replay")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
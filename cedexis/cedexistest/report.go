@@ -0,0 +1,208 @@
+package cedexistest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+// AddRadarSeries adds Radar measurements of a platform.  A series with a market or country only
+// answers queries for that market or country.
+func (s *Server) AddRadarSeries(series *cedexis.RadarSeries) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.radar = append(s.radar, series)
+}
+
+// serveRadarReport answers with the samples of the matching series in the query's time window.
+// The interval is ignored, samples are returned as added.
+func (s *Server) serveRadarReport(w http.ResponseWriter, method string, query url.Values) {
+	if method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", method+" not allowed")
+		return
+	}
+
+	platformID, err := strconv.Atoi(query.Get("platformId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Invalid platformId")
+		return
+	}
+	metric, err := cedexis.ParseRadarMetric(query.Get("metric"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+	start, err := time.Parse(time.RFC3339, query.Get("start"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Invalid start")
+		return
+	}
+	end, err := time.Parse(time.RFC3339, query.Get("end"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Invalid end")
+		return
+	}
+
+	resp := map[string]interface{}{
+		"platformId": platformID,
+		"metric":     metric.String(),
+	}
+	if m := query.Get("market"); m != "" {
+		resp["market"] = m
+	}
+	if c := query.Get("country"); c != "" {
+		resp["country"] = c
+	}
+
+	samples := []cedexis.RadarSample{}
+	for _, series := range s.radar {
+		if series.PlatformID != platformID || series.Metric != metric ||
+			marketCode(series.Market) != query.Get("market") || series.Country != query.Get("country") {
+			continue
+		}
+
+		for _, sample := range series.Samples {
+			if !sample.Time.Before(start) && sample.Time.Before(end) {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	resp["samples"] = samples
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func marketCode(m *cedexis.Market) string {
+	if m == nil {
+		return ""
+	}
+	return string(*m)
+}
+
+type sonarTransition struct {
+	platformID int
+	transition cedexis.SonarTransition
+}
+
+// SetSonarStatus sets the current Sonar state of a platform
+func (s *Server) SetSonarStatus(status *cedexis.SonarStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.sonar {
+		if existing.PlatformID == status.PlatformID {
+			s.sonar[i] = status
+			return
+		}
+	}
+	s.sonar = append(s.sonar, status)
+}
+
+// AddSonarTransition adds a Sonar state change of a platform to its history
+func (s *Server) AddSonarTransition(platformID int, t cedexis.SonarTransition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, sonarTransition{platformID, t})
+}
+
+// serveSonarStatus answers with the status of all platforms, or of "/{id}"
+func (s *Server) serveSonarStatus(w http.ResponseWriter, method string, rest string) {
+	if method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", method+" not allowed")
+		return
+	}
+
+	if rest == "" {
+		statuses := make([]map[string]interface{}, 0, len(s.sonar))
+		for _, status := range s.sonar {
+			statuses = append(statuses, sonarStatusObject(status))
+		}
+		writeJSON(w, http.StatusOK, statuses)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(rest, "/"))
+	if err == nil {
+		for _, status := range s.sonar {
+			if status.PlatformID == id {
+				writeJSON(w, http.StatusOK, sonarStatusObject(status))
+				return
+			}
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "notFound", "No sonar status "+rest)
+}
+
+// serveSonarHistory answers with the transitions of a platform in the query's time window
+func (s *Server) serveSonarHistory(w http.ResponseWriter, method string, query url.Values) {
+	if method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", method+" not allowed")
+		return
+	}
+
+	platformID, err := strconv.Atoi(query.Get("platformId"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Invalid platformId")
+		return
+	}
+	start, err := time.Parse(time.RFC3339, query.Get("start"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Invalid start")
+		return
+	}
+	end, err := time.Parse(time.RFC3339, query.Get("end"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", "Invalid end")
+		return
+	}
+
+	transitions := []map[string]interface{}{}
+	for _, h := range s.history {
+		t := h.transition
+		if h.platformID != platformID || t.Time.Before(start) || !t.Time.Before(end) {
+			continue
+		}
+
+		obj := map[string]interface{}{
+			"timestamp": t.Time,
+			"from":      t.From.String(),
+			"to":        t.To.String(),
+		}
+		if t.Market != nil {
+			obj["market"] = *t.Market
+		}
+		if t.Reason != "" {
+			obj["reason"] = t.Reason
+		}
+		transitions = append(transitions, obj)
+	}
+
+	writeJSON(w, http.StatusOK, transitions)
+}
+
+func sonarStatusObject(status *cedexis.SonarStatus) map[string]interface{} {
+	markets := make([]map[string]interface{}, 0, len(status.Markets))
+	for _, m := range status.Markets {
+		markets = append(markets, map[string]interface{}{
+			"market":      m.Market,
+			"state":       m.State.String(),
+			"since":       m.Since,
+			"lastChecked": m.LastChecked,
+		})
+	}
+
+	return map[string]interface{}{
+		"platformId":  status.PlatformID,
+		"state":       status.State.String(),
+		"since":       status.Since,
+		"lastChecked": status.LastChecked,
+		"markets":     markets,
+	}
+}
@@ -0,0 +1,749 @@
+// Package cedexistest provides an in-process fake of the Cedexis API, for testing code built on
+// cedexis.Client without talking to portal.cedexis.com.
+//
+// The fake keeps all state in memory and supports injecting faults (rate limiting, server
+// errors, latency and malformed error bodies):
+//
+//	srv := cedexistest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client(ctx)
+//	srv.InjectFault(cedexistest.Fault{Method: "GET", Status: 429, Times: 1})
+package cedexistest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+const (
+	apiPrefix = "/api/v2"
+	tokenPath = "/api/oauth/token"
+
+	// Token is the OAuth access token issued by the fake
+	Token = "cedexistest-token"
+
+	// OwnerID is the customer ID the fake assigns to created resources
+	OwnerID = 1000
+)
+
+// Fault describes a failure to inject into matching requests
+type Fault struct {
+	// Method matches the request method, empty matches any
+	Method string
+
+	// Path is a prefix matched against the request path below the API base URL
+	// (e.g. "/config/platforms.json"), empty matches any
+	Path string
+
+	// Latency delays the response
+	Latency time.Duration
+
+	// Status is the failure status to return, zero only applies Latency
+	Status int
+
+	// Body is returned verbatim with Status, by default a well-formed Cedexis error is returned
+	Body string
+
+	// RetryAfter sets the Retry-After header with Status
+	RetryAfter string
+
+	// Times is the number of requests to affect, zero affects all
+	Times int
+}
+
+// Request records a request handled by the fake
+type Request struct {
+	Method string
+
+	// Path is relative to the API base URL
+	Path string
+
+	Query url.Values
+
+	Body []byte
+}
+
+// Server is a fake Cedexis API server
+type Server struct {
+	// URL is the root of the fake portal
+	URL string
+
+	server *httptest.Server
+
+	mu         sync.Mutex
+	nextID     int
+	platforms  *collection
+	alerts     *collection
+	zones      *collection
+	records    *collection
+	apps       *collection
+	community  []*cedexis.PlatformInfo
+	system     []*cedexis.PlatformInfo
+	categories []*cedexis.NameID
+	countries  []*cedexis.Country
+	fusion     []*cedexis.FusionArchetype
+	radar      []*cedexis.RadarSeries
+	sonar      []*cedexis.SonarStatus
+	history    []sonarTransition
+	faults     []*Fault
+	requests   []Request
+}
+
+// NewServer starts a fake Cedexis API server, callers should Close it when done
+func NewServer() *Server {
+	s := &Server{
+		nextID:    1,
+		platforms: newCollection("platform", "name"),
+		alerts:    newCollection("alert", "name"),
+		zones:     newCollection("zone", "domainName"),
+		records:   newCollection("record", ""),
+		apps:      newCollection("application", "name"),
+		categories: []*cedexis.NameID{
+			nameID(cedexis.PlatformCategoryCloudComputing, "Cloud Computing"),
+			nameID(cedexis.PlatformCategoryDynamicContent, "Dynamic Content"),
+			nameID(cedexis.PlatformCategoryDeliveryNetwork, "Delivery Network"),
+			nameID(cedexis.PlatformCategoryCloudStorage, "Cloud Storage"),
+			nameID(cedexis.PlatformCategorySecureObjectDelivery, "Secure Object Delivery"),
+			nameID(cedexis.PlatformCategoryManagedDNS, "Managed DNS"),
+		},
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// BaseURL is the API base URL, for cedexis.WithBaseURL
+func (s *Server) BaseURL() string {
+	return s.URL + apiPrefix
+}
+
+// TokenURL is the OAuth token URL, for cedexis.WithTokenURL
+func (s *Server) TokenURL() string {
+	return s.URL + tokenPath
+}
+
+// Client creates a client for the fake server, options are applied after those targeting the fake
+func (s *Server) Client(ctx context.Context, opts ...cedexis.ClientOption) *cedexis.Client {
+	opts = append([]cedexis.ClientOption{
+		cedexis.WithBaseURL(s.BaseURL()),
+		cedexis.WithTokenURL(s.TokenURL()),
+	}, opts...)
+
+	return cedexis.NewClientWithOptions(ctx, "cedexistest", "secret", opts...)
+}
+
+// InjectFault adds a fault, faults are matched in the order they were added
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns the API requests handled so far (excluding token requests)
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// AddCommunityPlatform adds a community platform (archetype), assigning an ID if not set
+func (s *Server) AddCommunityPlatform(p *cedexis.PlatformInfo) *cedexis.PlatformInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p = s.newInfo(p)
+	s.community = append(s.community, p)
+	return p
+}
+
+// AddSystemPlatform adds a system platform, assigning an ID if not set
+func (s *Server) AddSystemPlatform(p *cedexis.PlatformInfo) *cedexis.PlatformInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p = s.newInfo(p)
+	s.system = append(s.system, p)
+	return p
+}
+
+// AddCountry adds a country to the country report
+func (s *Server) AddCountry(c *cedexis.Country) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.countries = append(s.countries, c)
+}
+
+// AddFusionArchetype adds a Fusion archetype to those available
+func (s *Server) AddFusionArchetype(a *cedexis.FusionArchetype) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fusion = append(s.fusion, a)
+}
+
+// AddPrivatePlatform adds a private platform directly, returning the stored configuration
+func (s *Server) AddPrivatePlatform(p *cedexis.PlatformConfig) (*cedexis.PlatformConfig, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, err := toObject(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.platforms.checkUnique(obj, 0); err != nil {
+		return nil, err
+	}
+
+	s.platforms.put(s.newID(), s.platformDefaults(obj))
+
+	out := &cedexis.PlatformConfig{}
+	return out, fromObject(obj, out)
+}
+
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *Server) newInfo(p *cedexis.PlatformInfo) *cedexis.PlatformInfo {
+	cp := *p
+	if cp.ID == nil {
+		id := s.newID()
+		cp.ID = &id
+	}
+	return &cp
+}
+
+func (s *Server) platformDefaults(obj map[string]interface{}) map[string]interface{} {
+	now := time.Now().UTC().Format(time.RFC3339)
+	obj["ownerId"] = OwnerID
+	obj["created"] = now
+	obj["modified"] = now
+	if _, ok := obj["enabled"]; !ok {
+		obj["enabled"] = true
+	}
+	return obj
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == tokenPath {
+		s.serveToken(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		writeError(w, http.StatusNotFound, "notFound", "Unknown path "+r.URL.Path)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "Missing or invalid access token")
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "badRequest", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Query: r.URL.Query(), Body: body})
+	fault := s.matchFault(r.Method, path)
+	s.mu.Unlock()
+
+	if fault != nil && s.applyFault(w, r, fault) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.route(w, r.Method, path, r.URL.Query(), body)
+}
+
+func (s *Server) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", "Token requests must be POST")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": Token,
+		"token_type":   "bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (s *Server) matchFault(method string, path string) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return &matched
+	}
+
+	return nil
+}
+
+// applyFault applies latency and any failure status, returning true if the response was written
+func (s *Server) applyFault(w http.ResponseWriter, r *http.Request, f *Fault) bool {
+	if f.Latency > 0 {
+		select {
+		case <-time.After(f.Latency):
+		case <-r.Context().Done():
+			return true
+		}
+	}
+
+	if f.Status == 0 {
+		return false
+	}
+
+	if f.RetryAfter != "" {
+		w.Header().Set("Retry-After", f.RetryAfter)
+	}
+
+	if f.Body != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.Status)
+		w.Write([]byte(f.Body))
+		return true
+	}
+
+	writeError(w, f.Status, "injectedFault", fmt.Sprintf("Injected fault (%d)", f.Status))
+	return true
+}
+
+func (s *Server) route(w http.ResponseWriter, method string, path string, query url.Values, body []byte) {
+	switch {
+	case path == "/meta/system.json/ping":
+		writeJSON(w, http.StatusOK, map[string]string{"result": "pong"})
+	case path == "/config/platforms.json/providerCategories":
+		writeJSON(w, http.StatusOK, s.categories)
+	case path == "/config/platforms.json/fusionArchetypes":
+		writeJSON(w, http.StatusOK, s.fusion)
+	case strings.HasPrefix(path, "/config/platforms.json"):
+		s.serveCollection(w, method, strings.TrimPrefix(path, "/config/platforms.json"), body, s.platforms, s.platformDefaults)
+	case strings.HasPrefix(path, "/reporting/platforms.json"):
+		s.servePlatformReport(w, method, strings.TrimPrefix(path, "/reporting/platforms.json"))
+	case strings.HasPrefix(path, "/config/alerts.json"):
+		s.serveCollection(w, method, strings.TrimPrefix(path, "/config/alerts.json"), body, s.alerts, nil)
+	case strings.HasPrefix(path, "/config/authdns.json/record"):
+		s.serveRecords(w, method, strings.TrimPrefix(path, "/config/authdns.json/record"), body)
+	case strings.HasPrefix(path, "/config/authdns.json"):
+		s.serveZones(w, method, strings.TrimPrefix(path, "/config/authdns.json"), body)
+	case strings.HasPrefix(path, "/config/applications/dns.json"):
+		s.serveCollection(w, method, strings.TrimPrefix(path, "/config/applications/dns.json"), body, s.apps, nil)
+	case path == "/reporting/countries.json":
+		writeJSON(w, http.StatusOK, s.countries)
+	case path == "/reporting/radar.json":
+		s.serveRadarReport(w, method, query)
+	case path == "/reporting/sonar.json/history":
+		s.serveSonarHistory(w, method, query)
+	case strings.HasPrefix(path, "/reporting/sonar.json"):
+		s.serveSonarStatus(w, method, strings.TrimPrefix(path, "/reporting/sonar.json"))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "Unknown path "+path)
+	}
+}
+
+// serveCollection implements list/create on the collection, and get/update/delete on "/{id}"
+func (s *Server) serveCollection(w http.ResponseWriter, method string, rest string, body []byte,
+	c *collection, defaults func(map[string]interface{}) map[string]interface{}) {
+
+	if rest == "" {
+		switch method {
+		case "GET":
+			writeJSON(w, http.StatusOK, c.list())
+		case "POST":
+			obj, ok := decodeObject(w, body)
+			if !ok {
+				return
+			}
+			if err := c.checkUnique(obj, 0); err != nil {
+				writeAPIError(w, err)
+				return
+			}
+			if defaults != nil {
+				obj = defaults(obj)
+			}
+			writeJSON(w, http.StatusOK, c.put(s.newID(), obj))
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", method+" not allowed")
+		}
+		return
+	}
+
+	id, ok := parseID(w, rest)
+	if !ok {
+		return
+	}
+
+	existing := c.items[id]
+	if existing == nil {
+		writeError(w, http.StatusNotFound, "notFound", fmt.Sprintf("No %s with id %d", c.kind, id))
+		return
+	}
+
+	switch method {
+	case "GET":
+		writeJSON(w, http.StatusOK, existing)
+	case "PUT":
+		obj, ok := decodeObject(w, body)
+		if !ok {
+			return
+		}
+		if err := c.checkUnique(obj, id); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		for _, k := range []string{"ownerId", "created"} {
+			if v, ok := existing[k]; ok {
+				obj[k] = v
+			}
+		}
+		if _, ok := existing["modified"]; ok {
+			obj["modified"] = time.Now().UTC().Format(time.RFC3339)
+		}
+		writeJSON(w, http.StatusOK, c.put(id, obj))
+	case "PATCH":
+		changes, ok := decodeObject(w, body)
+		if !ok {
+			return
+		}
+		obj := mergeObjects(existing, changes)
+		if err := c.checkUnique(obj, id); err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		if _, ok := existing["modified"]; ok {
+			obj["modified"] = time.Now().UTC().Format(time.RFC3339)
+		}
+		writeJSON(w, http.StatusOK, c.put(id, obj))
+	case "DELETE":
+		delete(c.items, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", method+" not allowed")
+	}
+}
+
+func (s *Server) servePlatformReport(w http.ResponseWriter, method string, rest string) {
+	if method != "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", method+" not allowed")
+		return
+	}
+
+	private := make([]*cedexis.PlatformInfo, 0, len(s.platforms.items))
+	for _, obj := range s.platforms.list() {
+		info := &cedexis.PlatformInfo{}
+		fromObject(obj, info)
+		private = append(private, info)
+	}
+
+	switch rest {
+	case "":
+		all := append(append(private, s.community...), s.system...)
+		writeJSON(w, http.StatusOK, all)
+	case "/private":
+		writeJSON(w, http.StatusOK, private)
+	case "/community":
+		writeJSON(w, http.StatusOK, orEmpty(s.community))
+	case "/system":
+		writeJSON(w, http.StatusOK, orEmpty(s.system))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "Unknown platform report "+rest)
+	}
+}
+
+// serveZones serves zones, merging in their records
+func (s *Server) serveZones(w http.ResponseWriter, method string, rest string, body []byte) {
+	if method == "GET" {
+		if rest == "" {
+			zones := s.zones.list()
+			for i, z := range zones {
+				zones[i] = s.withRecords(z)
+			}
+			writeJSON(w, http.StatusOK, zones)
+			return
+		}
+
+		if id, err := strconv.Atoi(strings.TrimPrefix(rest, "/")); err == nil && s.zones.items[id] != nil {
+			writeJSON(w, http.StatusOK, s.withRecords(s.zones.items[id]))
+			return
+		}
+	}
+
+	if method == "DELETE" {
+		if id, err := strconv.Atoi(strings.TrimPrefix(rest, "/")); err == nil {
+			for rid, r := range s.records.items {
+				if zid, _ := intField(r, "dnsZoneId"); zid == id {
+					delete(s.records.items, rid)
+				}
+			}
+		}
+	}
+
+	s.serveCollection(w, method, rest, body, s.zones, func(obj map[string]interface{}) map[string]interface{} {
+		delete(obj, "importContents")
+		delete(obj, "records")
+		return obj
+	})
+}
+
+func (s *Server) withRecords(zone map[string]interface{}) map[string]interface{} {
+	id, _ := intField(zone, "id")
+
+	records := []interface{}{}
+	for _, r := range s.records.list() {
+		if zid, _ := intField(r, "dnsZoneId"); zid == id {
+			records = append(records, r)
+		}
+	}
+
+	out := make(map[string]interface{}, len(zone)+1)
+	for k, v := range zone {
+		out[k] = v
+	}
+	out["records"] = records
+	return out
+}
+
+func (s *Server) serveRecords(w http.ResponseWriter, method string, rest string, body []byte) {
+	if method == "POST" && rest == "" {
+		obj, ok := decodeObject(w, body)
+		if !ok {
+			return
+		}
+		zoneID, _ := intField(obj, "dnsZoneId")
+		if s.zones.items[zoneID] == nil {
+			writeError(w, http.StatusBadRequest, "invalidZone", fmt.Sprintf("No zone with id %d", zoneID))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.records.put(s.newID(), obj))
+		return
+	}
+
+	if rest == "" && method == "GET" {
+		writeError(w, http.StatusMethodNotAllowed, "methodNotAllowed", "Records are listed by zone")
+		return
+	}
+
+	s.serveCollection(w, method, rest, body, s.records, nil)
+}
+
+// collection holds resources of one kind as generic JSON objects
+type collection struct {
+	kind    string
+	nameKey string
+	items   map[int]map[string]interface{}
+}
+
+func newCollection(kind string, nameKey string) *collection {
+	return &collection{kind: kind, nameKey: nameKey, items: map[int]map[string]interface{}{}}
+}
+
+func (c *collection) put(id int, obj map[string]interface{}) map[string]interface{} {
+	obj["id"] = id
+	c.items[id] = obj
+	return obj
+}
+
+func (c *collection) list() []map[string]interface{} {
+	ids := make([]int, 0, len(c.items))
+	for id := range c.items {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	result := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		result[i] = c.items[id]
+	}
+	return result
+}
+
+// checkUnique rejects objects whose name duplicates another (than self) in the collection
+func (c *collection) checkUnique(obj map[string]interface{}, self int) error {
+	if c.nameKey == "" {
+		return nil
+	}
+
+	name, ok := obj[c.nameKey].(string)
+	if !ok || name == "" {
+		return &cedexis.APIError{
+			StatusCode: http.StatusBadRequest,
+			Details: []cedexis.ErrorDetail{{
+				UserMessage: fmt.Sprintf("A %s requires a %s", c.kind, c.nameKey),
+				Field:       c.nameKey,
+				ErrorCode:   "required",
+			}},
+		}
+	}
+
+	for id, other := range c.items {
+		if id != self && strings.EqualFold(fmt.Sprint(other[c.nameKey]), name) {
+			return &cedexis.APIError{
+				StatusCode: http.StatusConflict,
+				Details: []cedexis.ErrorDetail{{
+					UserMessage: fmt.Sprintf("A %s named '%s' already exists", c.kind, name),
+					Field:       c.nameKey,
+					ErrorCode:   "duplicate",
+				}},
+			}
+		}
+	}
+
+	return nil
+}
+
+func nameID(id cedexis.PlatformCategory, name string) *cedexis.NameID {
+	return &cedexis.NameID{ID: &id, Name: &name}
+}
+
+func orEmpty(p []*cedexis.PlatformInfo) []*cedexis.PlatformInfo {
+	if p == nil {
+		return []*cedexis.PlatformInfo{}
+	}
+	return p
+}
+
+func toObject(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]interface{}{}
+	return obj, json.Unmarshal(data, &obj)
+}
+
+func fromObject(obj map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func intField(obj map[string]interface{}, key string) (int, bool) {
+	switch v := obj[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func parseID(w http.ResponseWriter, rest string) (int, bool) {
+	id, err := strconv.Atoi(strings.TrimPrefix(rest, "/"))
+	if err != nil || !strings.HasPrefix(rest, "/") {
+		writeError(w, http.StatusNotFound, "notFound", "Invalid id '"+rest+"'")
+		return 0, false
+	}
+	return id, true
+}
+
+// mergeObjects applies changes to a copy of obj, recursing into nested objects
+func mergeObjects(obj map[string]interface{}, changes map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		merged[k] = v
+	}
+
+	for k, v := range changes {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if cur, ok := merged[k].(map[string]interface{}); ok {
+				merged[k] = mergeObjects(cur, sub)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+func decodeObject(w http.ResponseWriter, body []byte) (map[string]interface{}, bool) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		writeError(w, http.StatusBadRequest, "invalidJson", "Malformed request: "+err.Error())
+		return nil, false
+	}
+	return obj, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*cedexis.APIError)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "internal", err.Error())
+		return
+	}
+
+	writeJSON(w, apiErr.StatusCode, map[string]interface{}{
+		"httpStatus":   apiErr.StatusCode,
+		"errorDetails": apiErr.Details,
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, code string, msg string) {
+	writeAPIError(w, &cedexis.APIError{
+		StatusCode: status,
+		Details:    []cedexis.ErrorDetail{{UserMessage: msg, DeveloperMessage: msg, ErrorCode: code}},
+	})
+}
@@ -4,76 +4,251 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"fmt"
+	"io"
 	"io/ioutil"
+	"log"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
-const baseURL = "https://portal.cedexis.com/api/v2"
+const (
+	defaultBaseURL  = "https://portal.cedexis.com/api/v2"
+	defaultTokenURL = "https://portal.cedexis.com/api/oauth/token"
+	userAgent       = "github.com/ctxkenb/cedexis-golang"
+)
+
+// Client implements a client for the Cedexis API
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	userAgent   string
+	retryPolicy RetryPolicy
+	logger      Logger
+	limiter     *RateLimiter
+
+	instrumentation Instrumentation
+	tracer          trace.Tracer
+	propagator      propagation.TextMapPropagator
+	changeLog       *ChangeLog
+
+	cache *cache
+}
+
+// ClientOption configures optional behaviour of a Client, see NewClientWithOptions
+type ClientOption func(*clientOptions)
 
-type cedexisError struct {
-	DeveloperMessage string `json:"developerMessage"`
-	UserMessage      string `json:"userMessage"`
-	Field            string `json:"field"`
-	ErrorCode        string `json:"errorCode"`
-	MoreInfo         string `json:"moreInfo"`
-	RootCause        string `json:"rootCause"`
+type clientOptions struct {
+	baseURL         string
+	tokenURL        string
+	transport       http.RoundTripper
+	timeout         time.Duration
+	userAgentSuffix string
+	retryPolicy     RetryPolicy
+	logger          Logger
+	trace           TraceMode
+	cacheTTLs       map[CacheKind]time.Duration
+	cacheDisabled   bool
+	limiter         *RateLimiter
+	instrumentation Instrumentation
+	tracerProvider  trace.TracerProvider
+	propagator      propagation.TextMapPropagator
+	changeLog       *ChangeLog
 }
 
-type cedexisErrorResponse struct {
-	HTTPStatus   int            `json:"httpStatus"`
-	ErrorDetails []cedexisError `json:"errorDetails"`
+// WithBaseURL overrides the API base URL (default https://portal.cedexis.com/api/v2)
+func WithBaseURL(url string) ClientOption {
+	return func(o *clientOptions) {
+		o.baseURL = strings.TrimRight(url, "/")
+	}
 }
 
-// Client implements a client for the Cedexis API
-type Client struct {
-	httpClient *http.Client
+// WithTokenURL overrides the OAuth token URL (default https://portal.cedexis.com/api/oauth/token)
+func WithTokenURL(url string) ClientOption {
+	return func(o *clientOptions) {
+		o.tokenURL = url
+	}
+}
+
+// WithTransport sets the HTTP transport used for API and token requests
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(o *clientOptions) {
+		o.transport = rt
+	}
+}
+
+// WithTimeout sets an overall timeout for each HTTP request
+func WithTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithUserAgentSuffix appends a product token to the User-Agent header
+func WithUserAgentSuffix(suffix string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgentSuffix = suffix
+	}
+}
+
+// WithRetryPolicy overrides DefaultRetryPolicy, nil disables retries
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(o *clientOptions) {
+		o.retryPolicy = p
+	}
+}
+
+// WithLogger sends diagnostic output (retries, tracing) to a logger, by default it is discarded
+func WithLogger(l Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = l
+	}
+}
+
+// WithTrace enables logging of HTTP exchanges, to the WithLogger logger or else stderr
+func WithTrace(mode TraceMode) ClientOption {
+	return func(o *clientOptions) {
+		o.trace = mode
+	}
+}
+
+// WithCacheTTL overrides how long resources of a kind are cached (DefaultCacheTTL)
+func WithCacheTTL(kind CacheKind, ttl time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		if o.cacheTTLs == nil {
+			o.cacheTTLs = map[CacheKind]time.Duration{}
+		}
+		o.cacheTTLs[kind] = ttl
+	}
+}
+
+// WithCacheDisabled turns off caching, every read goes to Cedexis
+func WithCacheDisabled() ClientOption {
+	return func(o *clientOptions) {
+		o.cacheDisabled = true
+	}
+}
+
+// WithRateLimit throttles the client to requestsPerSecond, allowing bursts of up to burst requests
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(o *clientOptions) {
+		o.limiter = NewRateLimiter(requestsPerSecond, burst)
+	}
+}
 
-	zoneCache                map[int]*Zone
-	privatePlatformListCache map[int]*PlatformInfo
-	privatePlatformCache     map[int]*PlatformConfig
-	appCache                 map[int]*Application
-	countriesCache           map[int]*Country
+// WithRateLimiter throttles the client with an existing limiter, which may be shared with other clients
+func WithRateLimiter(l *RateLimiter) ClientOption {
+	return func(o *clientOptions) {
+		o.limiter = l
+	}
 }
 
 // NewClient creates a new Cedexis API client
 func NewClient(ctx context.Context, clientID string, clientSecret string) *Client {
+	return NewClientWithOptions(ctx, clientID, clientSecret)
+}
+
+// NewClientWithOptions creates a new Cedexis API client, customized by options
+func NewClientWithOptions(ctx context.Context, clientID string, clientSecret string, opts ...ClientOption) *Client {
+	o := clientOptions{
+		baseURL:     defaultBaseURL,
+		tokenURL:    defaultTokenURL,
+		retryPolicy: DefaultRetryPolicy(),
+		propagator:  propagation.TraceContext{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	config := clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
-		TokenURL:     "https://portal.cedexis.com/api/oauth/token",
+		TokenURL:     o.tokenURL,
+	}
+
+	if o.trace != TraceOff {
+		if o.logger == nil {
+			o.logger = log.New(os.Stderr, "cedexis: ", log.LstdFlags)
+		}
+
+		base := o.transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		o.transport = &tracingTransport{base: base, logger: o.logger, mode: o.trace}
+	}
+
+	// The oauth2 package picks up the base client (used for token requests
+	// and wrapped for API requests) from the context.
+	if o.transport != nil || o.timeout != 0 {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: o.transport, Timeout: o.timeout})
+	}
+
+	httpClient := config.Client(ctx)
+	httpClient.Timeout = o.timeout
+
+	ua := userAgent
+	if o.userAgentSuffix != "" {
+		ua += " " + o.userAgentSuffix
+	}
+
+	if o.tracerProvider == nil {
+		o.tracerProvider = defaultTracerProvider()
+	}
+
+	var clientCache *cache
+	if !o.cacheDisabled {
+		clientCache = newCache(o.cacheTTLs)
 	}
 
 	return &Client{
-		httpClient:               config.Client(ctx),
-		zoneCache:                map[int]*Zone{},
-		privatePlatformListCache: map[int]*PlatformInfo{},
-		privatePlatformCache:     map[int]*PlatformConfig{},
-		appCache:                 map[int]*Application{},
+		httpClient:  httpClient,
+		baseURL:     o.baseURL,
+		userAgent:   ua,
+		retryPolicy: o.retryPolicy,
+		logger:      o.logger,
+		limiter:     o.limiter,
+		cache:       clientCache,
+
+		instrumentation: o.instrumentation,
+		tracer:          o.tracerProvider.Tracer(tracerName),
+		propagator:      o.propagator,
+		changeLog:       o.changeLog,
 	}
 }
 
-func (c *Client) delete(url string) error {
-	_, err := c.doHTTP("DELETE", url, nil)
+func (c *Client) delete(ctx context.Context, url string) error {
+	if c.changeLog != nil {
+		return c.dryRun("DELETE", url, nil, nil)
+	}
+
+	_, err := c.doHTTP(ctx, "DELETE", url, nil)
 	return err
 }
 
-func (c *Client) getJSON(url string, recv interface{}) error {
-	return c.doJSON("GET", url, nil, recv)
+func (c *Client) getJSON(ctx context.Context, url string, recv interface{}) error {
+	return c.doJSON(ctx, "GET", url, nil, recv)
+}
+
+func (c *Client) postJSON(ctx context.Context, url string, send interface{}, recv interface{}) error {
+	return c.doJSON(ctx, "POST", url, send, recv)
 }
 
-func (c *Client) postJSON(url string, send interface{}, recv interface{}) error {
-	return c.doJSON("POST", url, send, recv)
+func (c *Client) putJSON(ctx context.Context, url string, send interface{}, recv interface{}) error {
+	return c.doJSON(ctx, "PUT", url, send, recv)
 }
 
-func (c *Client) putJSON(url string, send interface{}, recv interface{}) error {
-	return c.doJSON("PUT", url, send, recv)
+func (c *Client) patchJSON(ctx context.Context, url string, send interface{}, recv interface{}) error {
+	return c.doJSON(ctx, "PATCH", url, send, recv)
 }
 
-func (c *Client) doJSON(method string, url string, send interface{}, recv interface{}) error {
+func (c *Client) doJSON(ctx context.Context, method string, url string, send interface{}, recv interface{}) error {
 	data := []byte{}
 	var err error
 	if send != nil {
@@ -83,7 +258,11 @@ func (c *Client) doJSON(method string, url string, send interface{}, recv interf
 		}
 	}
 
-	resp, err := c.doHTTP(method, url, data)
+	if c.changeLog != nil && method != "GET" {
+		return c.dryRun(method, url, data, recv)
+	}
+
+	resp, err := c.doHTTP(ctx, method, url, data)
 	if err != nil {
 		return err
 	}
@@ -105,55 +284,123 @@ func (c *Client) doJSON(method string, url string, send interface{}, recv interf
 	return nil
 }
 
-func (c *Client) doHTTP(method string, url string, toSend []byte) (*http.Response, error) {
-	delay := time.Duration(1)
+func (c *Client) doHTTP(ctx context.Context, method string, url string, toSend []byte) (*http.Response, error) {
+	resource := c.resourceOf(url)
+
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			wait, err := c.limiter.wait(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if wait > 0 && c.instrumentation != nil {
+				c.instrumentation.RateLimited(resource, method, wait)
+			}
+		}
 
-	for {
 		req, err := http.NewRequest(method, url, bytes.NewReader(toSend))
 		if err != nil {
 			return nil, err
 		}
+		req = req.WithContext(ctx)
 
 		if toSend != nil {
 			req.Header.Set("Content-Type", "application/json; charset=utf-8")
 		}
-		req.Header.Set("User-Agent", "github.com/ctxkenb/cedexis-golang")
+		req.Header.Set("User-Agent", c.userAgent)
 
-		// dump, err := httputil.DumpRequestOut(req, true)
-		// fmt.Printf("%v\n", string(dump))
+		req, span := c.startAttemptSpan(req, resource, attempt)
 
+		start := time.Now()
 		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, err
+		endAttemptSpan(span, resp, err)
+		if c.instrumentation != nil {
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			c.instrumentation.RequestDone(resource, method, status, time.Since(start))
 		}
-		if resp.StatusCode == 429 {
-			fmt.Printf("Rate Limited, sleeping for %v\n", delay*time.Second)
-			time.Sleep(delay * time.Second)
-			delay = delay << 1
-			continue
+
+		if err == nil && resp.StatusCode < 400 {
+			return resp, nil
+		}
+
+		if c.retryPolicy != nil {
+			if delay, ok := c.retryPolicy.Retry(attempt, req, resp, err); ok {
+				if resp != nil {
+					io.Copy(ioutil.Discard, resp.Body)
+					resp.Body.Close()
+				}
+
+				c.logf("%s %s failed (%s), retrying in %v", method, url, failureReason(resp, err), delay)
+				if c.instrumentation != nil {
+					status := 0
+					if resp != nil {
+						status = resp.StatusCode
+					}
+					c.instrumentation.Retried(resource, method, status, delay)
+				}
+
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, err
+				}
+				continue
+			}
 		}
-		if resp.StatusCode >= 400 {
-			// dump, _ := httputil.DumpResponse(resp, true)
-			// fmt.Printf("%v\n", string(dump))
-			return nil, errorFromHTTPFailure(resp)
+
+		if err != nil {
+			return nil, err
 		}
 
-		return resp, nil
+		return nil, errorFromHTTPFailure(resp)
+	}
+}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
+
+func failureReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.Status
+}
+
+// sleepContext pauses for d, returning early with the context's error if it is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
 	}
 }
 
 func errorFromHTTPFailure(resp *http.Response) error {
 	defer resp.Body.Close()
-	body, errErr := ioutil.ReadAll(resp.Body)
-	if errErr != nil {
-		return fmt.Errorf("Call to Cedexis failed, error code %v", resp.StatusCode)
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.URL = resp.Request.URL.String()
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return apiErr
 	}
 
 	cedexisError := cedexisErrorResponse{}
-	errErr = json.Unmarshal(body, &cedexisError)
-	if errErr != nil {
-		return fmt.Errorf("Call to Cedexis failed, error code %v", resp.StatusCode)
+	if json.Unmarshal(body, &cedexisError) == nil {
+		apiErr.Details = cedexisError.ErrorDetails
 	}
 
-	return fmt.Errorf("Call to Cedexis failed, because '%s'", cedexisError.ErrorDetails[0].UserMessage)
+	return apiErr
 }
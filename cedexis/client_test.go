@@ -0,0 +1,511 @@
+package cedexis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+	"github.com/ctxkenb/cedexis-golang/cedexis/cedexistest"
+)
+
+var fastRetries = cedexis.WithRetryPolicy(&cedexis.BackoffRetryPolicy{
+	MaxAttempts:     3,
+	BaseDelay:       time.Millisecond,
+	MaxDelay:        10 * time.Millisecond,
+	RetryableStatus: cedexis.DefaultRetryableStatus,
+})
+
+func newTestClient(t *testing.T, opts ...cedexis.ClientOption) (*cedexistest.Server, *cedexis.Client) {
+	srv := cedexistest.NewServer()
+	t.Cleanup(srv.Close)
+
+	return srv, srv.Client(context.Background(), append([]cedexis.ClientOption{fastRetries}, opts...)...)
+}
+
+func countRequests(srv *cedexistest.Server, method string, path string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Method == method && r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestPing(t *testing.T) {
+	_, c := newTestClient(t)
+
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+}
+
+func TestPrivatePlatformLifecycle(t *testing.T) {
+	_, c := newTestClient(t)
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "Web servers", []string{"prod"}))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+	if p.ID == nil || p.OwnerID == nil {
+		t.Fatalf("Created platform missing server-owned fields: %+v", p)
+	}
+
+	byName, err := c.GetPrivatePlatformByName("WEB")
+	if err != nil || byName == nil || *byName.ID != *p.ID {
+		t.Errorf("GetPrivatePlatformByName got (%v, %v), want platform %d", byName, err, *p.ID)
+	}
+
+	displayName := "Web Servers"
+	p.DisplayName = &displayName
+	if err := c.UpdatePrivatePlatform(p); err != nil {
+		t.Fatalf("UpdatePrivatePlatform failed: %v", err)
+	}
+
+	got, err := c.GetPrivatePlatform(*p.ID)
+	if err != nil || *got.DisplayName != displayName {
+		t.Errorf("GetPrivatePlatform after update got (%v, %v), want display name %q", got, err, displayName)
+	}
+
+	if err := c.DeletePrivatePlatform(*p.ID); err != nil {
+		t.Fatalf("DeletePrivatePlatform failed: %v", err)
+	}
+
+	_, err = c.GetPrivatePlatform(*p.ID)
+	if !cedexis.IsNotFound(err) {
+		t.Errorf("GetPrivatePlatform after delete got %v, want not found", err)
+	}
+
+	platforms, err := c.GetPlatforms(cedexis.PlatformsTypePrivate)
+	if err != nil || len(platforms) != 0 {
+		t.Errorf("GetPlatforms after delete got (%v, %v), want none", platforms, err)
+	}
+}
+
+func TestPrivatePlatformConflict(t *testing.T) {
+	_, c := newTestClient(t)
+
+	if _, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil)); err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	_, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if !cedexis.IsConflict(err) {
+		t.Fatalf("Duplicate CreatePrivatePlatform got %v, want conflict", err)
+	}
+
+	var apiErr *cedexis.APIError
+	if !errors.As(err, &apiErr) || apiErr.Method != "POST" || len(apiErr.Details) != 1 || apiErr.Details[0].Field != "name" {
+		t.Errorf("Incorrect API error details: %+v", apiErr)
+	}
+}
+
+func TestAlertLifecycle(t *testing.T) {
+	_, c := newTestClient(t)
+
+	a := c.NewAlert("web-down", cedexis.AlertTypeSonar, 1, cedexis.AlertChangeToDown,
+		cedexis.AlertTimingImmediate, []string{"ops@example.com"}, 300)
+	created, err := c.CreateAlert(a)
+	if err != nil {
+		t.Fatalf("CreateAlert failed: %v", err)
+	}
+
+	threshold := 3
+	created.Threshold = &threshold
+	updated, err := c.UpdateAlert(created)
+	if err != nil || updated.Threshold == nil || *updated.Threshold != threshold {
+		t.Errorf("UpdateAlert got (%v, %v), want threshold %d", updated, err, threshold)
+	}
+
+	byName, err := c.GetAlertByName("web-down")
+	if err != nil || byName == nil || *byName.ID != *created.ID {
+		t.Errorf("GetAlertByName got (%v, %v), want alert %d", byName, err, *created.ID)
+	}
+
+	if err := c.DeleteAlert(*created.ID); err != nil {
+		t.Fatalf("DeleteAlert failed: %v", err)
+	}
+
+	if _, err := c.GetAlert(*created.ID); !cedexis.IsNotFound(err) {
+		t.Errorf("GetAlert after delete got %v, want not found", err)
+	}
+}
+
+func TestZoneAndRecords(t *testing.T) {
+	_, c := newTestClient(t)
+
+	// Populate the zone list cache, so record changes must update it
+	if _, err := c.GetZones(); err != nil {
+		t.Fatalf("GetZones failed: %v", err)
+	}
+
+	z, err := c.CreateZone("example.com", "Example", []string{"test"}, nil)
+	if err != nil {
+		t.Fatalf("CreateZone failed: %v", err)
+	}
+
+	ttl := 300
+	sub := "www"
+	rtype := cedexis.RecordTypeA
+	r := &cedexis.Record{DNSZoneID: z.ID, TTL: &ttl, SubdomainName: &sub, RecordType: &rtype}
+	r.SetResponseObject(&cedexis.AddressesResponse{Addresses: []string{"192.0.2.1"}})
+
+	created, err := c.CreateRecord(r)
+	if err != nil {
+		t.Fatalf("CreateRecord failed: %v", err)
+	}
+
+	newTTL := 60
+	created.TTL = &newTTL
+	if _, err := c.UpdateRecord(created); err != nil {
+		t.Fatalf("UpdateRecord failed: %v", err)
+	}
+
+	found, err := c.GetRecordByName("example.com", "WWW", cedexis.RecordTypeA)
+	if err != nil || found == nil || *found.TTL != newTTL {
+		t.Errorf("GetRecordByName got (%v, %v), want TTL %d", found, err, newTTL)
+	}
+
+	if err := c.DeleteZone(*z.ID); err != nil {
+		t.Fatalf("DeleteZone failed: %v", err)
+	}
+
+	if z, err := c.GetZoneByName("example.com"); err != nil || z != nil {
+		t.Errorf("GetZoneByName after delete got (%v, %v), want nil", z, err)
+	}
+}
+
+func TestApplicationLifecycle(t *testing.T) {
+	_, c := newTestClient(t)
+
+	id := 1
+	cname := "web.example.com"
+	app := cedexis.NewApplication("web", "Web", cedexis.ApplicationTypeOptimalRTT, "fallback.example.com", 80,
+		[]cedexis.ApplicationPlatform{{ID: &id, Cname: &cname}})
+
+	created, err := c.CreateApplication(app)
+	if err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+
+	desc := "Web servers"
+	created.Description = &desc
+	updated, err := c.UpdateApplication(created)
+	if err != nil || *updated.Description != desc {
+		t.Errorf("UpdateApplication got (%v, %v), want description %q", updated, err, desc)
+	}
+
+	if err := c.DeleteApplication(*created.ID); err != nil {
+		t.Fatalf("DeleteApplication failed: %v", err)
+	}
+
+	apps, err := c.GetApplications()
+	if err != nil || len(apps) != 0 {
+		t.Errorf("GetApplications after delete got (%v, %v), want none", apps, err)
+	}
+}
+
+func TestCountries(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	srv.AddCountry(&cedexis.Country{Location: cedexis.Location{ID: 1, ISOCode: "FR", Name: "France"}})
+
+	country, err := c.GetCountryByName("France")
+	if err != nil || country == nil || country.ISOCode != "FR" {
+		t.Errorf("GetCountryByName got (%v, %v), want FR", country, err)
+	}
+
+	// Second lookup is served from cache
+	c.GetCountries()
+	if n := countRequests(srv, "GET", "/reporting/countries.json"); n != 1 {
+		t.Errorf("Countries fetched %d times, want 1", n)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	srv.InjectFault(cedexistest.Fault{Method: "GET", Path: "/meta", Status: 429, RetryAfter: "0", Times: 1})
+	srv.InjectFault(cedexistest.Fault{Method: "GET", Path: "/meta", Status: 503, Times: 1})
+	if err := c.Ping(); err != nil {
+		t.Errorf("Ping with transient faults failed: %v", err)
+	}
+	if n := countRequests(srv, "GET", "/meta/system.json/ping"); n != 3 {
+		t.Errorf("Ping attempted %d times, want 3", n)
+	}
+
+	srv.InjectFault(cedexistest.Fault{Method: "POST", Status: 503, Times: 1})
+	_, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err == nil {
+		t.Errorf("CreatePrivatePlatform should fail on 503")
+	}
+	if n := countRequests(srv, "POST", "/config/platforms.json"); n != 1 {
+		t.Errorf("CreatePrivatePlatform attempted %d times, want 1", n)
+	}
+
+	srv.InjectFault(cedexistest.Fault{Status: 429})
+	if err := c.Ping(); !cedexis.IsRateLimited(err) {
+		t.Errorf("Ping when always rate limited got %v, want rate limited", err)
+	}
+}
+
+func TestMalformedErrorBody(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	srv.InjectFault(cedexistest.Fault{Status: 400, Body: "{not json"})
+	err := c.Ping()
+
+	var apiErr *cedexis.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 || len(apiErr.Details) != 0 {
+		t.Errorf("Ping with malformed error got %#v, want status 400 without details", err)
+	}
+	if !cedexis.IsValidation(err) {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	srv.InjectFault(cedexistest.Fault{Latency: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := c.PingWithContext(ctx); err == nil {
+		t.Errorf("Ping should fail when context deadline exceeded")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Ping took %v, should be cancelled promptly", elapsed)
+	}
+}
+
+func TestPlatformConfigs(t *testing.T) {
+	_, c := newTestClient(t)
+
+	cloud := cedexis.PlatformCategoryCloudComputing
+	web := cedexis.NewPrivatePlatform("web-eu", "Web EU", "", []string{"prod", "eu"})
+	web.Category = &cedexis.NameID{ID: &cloud}
+	for _, p := range []*cedexis.PlatformConfig{web,
+		cedexis.NewPrivatePlatform("web-us", "Web US", "", []string{"prod"}),
+		cedexis.NewPrivatePlatform("api-eu", "API EU", "", []string{"EU"}),
+	} {
+		if _, err := c.CreatePrivatePlatform(p); err != nil {
+			t.Fatalf("CreatePrivatePlatform failed: %v", err)
+		}
+	}
+
+	tag := "eu"
+	tests := []struct {
+		filter *cedexis.PlatformFilter
+		want   []string
+	}{
+		{nil, []string{"web-eu", "web-us", "api-eu"}},
+		{&cedexis.PlatformFilter{Tag: &tag}, []string{"web-eu", "api-eu"}},
+		{&cedexis.PlatformFilter{Category: &cloud}, []string{"web-eu"}},
+		{&cedexis.PlatformFilter{Name: regexp.MustCompile("^web-")}, []string{"web-eu", "web-us"}},
+	}
+
+	for _, test := range tests {
+		got, err := c.GetPlatformConfigs(test.filter)
+		if err != nil {
+			t.Fatalf("GetPlatformConfigs failed: %v", err)
+		}
+
+		names := make([]string, len(got))
+		for i, p := range got {
+			names[i] = *p.Name
+		}
+		if strings.Join(names, ",") != strings.Join(test.want, ",") {
+			t.Errorf("GetPlatformConfigs(%+v) got %v, want %v", test.filter, names, test.want)
+		}
+	}
+}
+
+func TestPatchPlatform(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "Web servers", []string{"prod"}))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	if err := c.DisablePlatform(*p.ID); err != nil {
+		t.Fatalf("DisablePlatform failed: %v", err)
+	}
+
+	enabled := true
+	if got, err := c.GetEnabledPlatforms(nil); err != nil || len(got) != 0 {
+		t.Errorf("GetEnabledPlatforms after disable got (%v, %v), want none", got, err)
+	}
+
+	displayName := "Web Servers"
+	patched, err := c.PatchPlatform(*p.ID, &cedexis.PlatformConfig{DisplayName: &displayName, Enabled: &enabled, Name: p.Name})
+	if err != nil {
+		t.Fatalf("PatchPlatform failed: %v", err)
+	}
+	if *patched.DisplayName != displayName || !*patched.Enabled || *patched.IntendedUse != "Web servers" {
+		t.Errorf("Incorrect patched platform: %+v", patched)
+	}
+
+	var patches []string
+	for _, r := range srv.Requests() {
+		if r.Method == "PATCH" {
+			patches = append(patches, string(r.Body))
+		}
+	}
+	if len(patches) != 2 || patches[0] != `{"enabled":false}` || patches[1] != `{"displayName":"Web Servers","enabled":true}` {
+		t.Errorf("Got PATCH bodies %v, want only changed fields", patches)
+	}
+
+	// No changes, nothing sent
+	if _, err := c.PatchPlatform(*p.ID, &cedexis.PlatformConfig{Enabled: &enabled}); err != nil {
+		t.Errorf("PatchPlatform without changes failed: %v", err)
+	}
+	if n := countRequests(srv, "PATCH", fmt.Sprintf("/config/platforms.json/%d", *p.ID)); n != 2 {
+		t.Errorf("Got %d PATCH requests, want 2", n)
+	}
+}
+
+func TestGetPlatformsCache(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	srv.AddCommunityPlatform(&cedexis.PlatformInfo{ID: intPtr(1), Name: strPtr("community")})
+	if _, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil)); err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	if _, err := c.GetPlatforms(cedexis.PlatformsTypePrivate); err != nil {
+		t.Fatalf("GetPlatforms failed: %v", err)
+	}
+	if _, err := c.GetPlatforms(cedexis.PlatformsTypeCommunity); err != nil {
+		t.Fatalf("GetPlatforms failed: %v", err)
+	}
+
+	private, err := c.GetPlatforms(cedexis.PlatformsTypePrivate)
+	if err != nil || len(private) != 1 || *private[0].Name != "web" {
+		t.Errorf("Private platforms after community listing got (%v, %v), want only web", private, err)
+	}
+}
+
+func TestCachedListOrder(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("web%d", i)
+		if _, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform(name, name, "", nil)); err != nil {
+			t.Fatalf("CreatePrivatePlatform failed: %v", err)
+		}
+	}
+
+	names := func() []string {
+		platforms, err := c.GetPlatforms(cedexis.PlatformsTypePrivate)
+		if err != nil {
+			t.Fatalf("GetPlatforms failed: %v", err)
+		}
+		result := make([]string, 0, len(platforms))
+		for _, p := range platforms {
+			result = append(result, *p.Name)
+		}
+		return result
+	}
+
+	want := names()
+	for i := 0; i < 10; i++ {
+		if got := names(); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("Cached platforms got %v, want server order %v", got, want)
+		}
+	}
+	if n := countRequests(srv, "GET", "/reporting/platforms.json/private"); n != 1 {
+		t.Errorf("Got %d list requests, want 1", n)
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestCreatePrivatePlatformValidatesSonar(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	spec := cedexis.NewPrivatePlatform("web", "Web", "", nil)
+	method := "FETCH"
+	spec.SonarConfig.Method = &method
+
+	if _, err := c.CreatePrivatePlatform(spec); err == nil || !strings.Contains(err.Error(), "FETCH") {
+		t.Errorf("Got error %v, want invalid sonar method", err)
+	}
+	if n := countRequests(srv, "POST", "/config/platforms.json"); n != 0 {
+		t.Errorf("Got %d POST requests, want none", n)
+	}
+}
+
+func TestWriteBackServerSonarConfig(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	// Settings Cedexis holds but the client wouldn't accept as new values
+	spec := cedexis.NewPrivatePlatform("web", "Web", "", nil)
+	method := "OPTIONS"
+	contentType := "application/json; charset=utf-8"
+	spec.SonarConfig.Method = &method
+	spec.SonarConfig.RequestContentType = &contentType
+	if _, err := srv.AddPrivatePlatform(spec); err != nil {
+		t.Fatalf("AddPrivatePlatform failed: %v", err)
+	}
+	p, err := c.GetPrivatePlatformByName("web")
+	if err != nil || p == nil {
+		t.Fatalf("GetPrivatePlatformByName got (%v, %v)", p, err)
+	}
+
+	displayName := "Web Servers"
+	p.DisplayName = &displayName
+	if err := c.UpdatePrivatePlatform(p); err != nil {
+		t.Errorf("UpdatePrivatePlatform failed: %v", err)
+	}
+
+	tags := []string{"prod"}
+	if _, err := c.PatchPlatform(*p.ID, &cedexis.PlatformConfig{Tags: &tags, SonarConfig: p.SonarConfig}); err != nil {
+		t.Errorf("PatchPlatform failed: %v", err)
+	}
+
+	name, cloneDisplayName := "web2", "Web 2"
+	if _, err := c.ClonePrivatePlatform(*p.ID, &cedexis.PlatformConfig{Name: &name, DisplayName: &cloneDisplayName}); err != nil {
+		t.Errorf("ClonePrivatePlatform failed: %v", err)
+	}
+
+	// Changing to an unknown method is still rejected
+	bad := "FETCH"
+	if _, err := c.PatchPlatform(*p.ID, &cedexis.PlatformConfig{SonarConfig: &cedexis.SonarConfig{Method: &bad}}); err == nil {
+		t.Errorf("Expected error patching sonar method %s", bad)
+	}
+}
+
+func TestUpdatePrivatePlatformValidatesEditedCopy(t *testing.T) {
+	_, c := newTestClient(t)
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	// Get, edit in place and update, as callers usually do
+	got, err := c.GetPrivatePlatform(*p.ID)
+	if err != nil {
+		t.Fatalf("GetPrivatePlatform failed: %v", err)
+	}
+	bad := "FETCH"
+	got.SonarConfig.Method = &bad
+	if err := c.UpdatePrivatePlatform(got); err == nil || !strings.Contains(err.Error(), "FETCH") {
+		t.Errorf("Got error %v, want invalid sonar method", err)
+	}
+}
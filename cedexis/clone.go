@@ -0,0 +1,88 @@
+package cedexis
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// serverOwnedFields are assigned by Cedexis to a new platform, so aren't copied by a clone
+var serverOwnedFields = []string{"id", "created", "modified", "ownerId"}
+
+// serverOwnedRadarFields identify the Radar provider of a platform, so aren't copied by a clone
+var serverOwnedRadarFields = []string{"subProviderId", "subProviderOwnerZoneId", "subProviderOwnerCustomerId"}
+
+// ClonePrivatePlatform creates a new private platform with the configuration of an existing one.
+// Non-nil fields of overrides replace those copied, at least the name and display name must be
+// overridden as they are unique.
+func (c *Client) ClonePrivatePlatform(id int, overrides *PlatformConfig) (*PlatformConfig, error) {
+	return c.ClonePrivatePlatformWithContext(context.Background(), id, overrides)
+}
+
+// ClonePrivatePlatformWithContext is ClonePrivatePlatform with a context for cancellation and deadlines.
+func (c *Client) ClonePrivatePlatformWithContext(ctx context.Context, id int, overrides *PlatformConfig) (*PlatformConfig, error) {
+	return c.ClonePrivatePlatformToWithContext(ctx, c, id, overrides)
+}
+
+// ClonePrivatePlatformTo is ClonePrivatePlatform creating the new platform with target, which may be
+// another account.  Substitution sources are platform IDs of this account, so aren't copied to another.
+func (c *Client) ClonePrivatePlatformTo(target *Client, id int, overrides *PlatformConfig) (*PlatformConfig, error) {
+	return c.ClonePrivatePlatformToWithContext(context.Background(), target, id, overrides)
+}
+
+// ClonePrivatePlatformToWithContext is ClonePrivatePlatformTo with a context for cancellation and deadlines.
+func (c *Client) ClonePrivatePlatformToWithContext(ctx context.Context, target *Client, id int, overrides *PlatformConfig) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "ClonePrivatePlatform")
+	defer func() { endSpan(span, err) }()
+
+	source, err := c.GetPrivatePlatformWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := cloneConfig(source, overrides, target != c)
+	if err != nil {
+		return nil, err
+	}
+
+	return target.createPrivatePlatform(ctx, spec, source)
+}
+
+// cloneConfig copies source without server-owned fields, applying overrides
+func cloneConfig(source *PlatformConfig, overrides *PlatformConfig, otherAccount bool) (*PlatformConfig, error) {
+	obj, err := jsonObject(source)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, k := range serverOwnedFields {
+		delete(obj, k)
+	}
+	if radar, ok := obj["radarConfig"].(map[string]interface{}); ok {
+		for _, k := range serverOwnedRadarFields {
+			delete(radar, k)
+		}
+	}
+	if otherAccount {
+		delete(obj, "platformSubstitutionSources")
+	}
+
+	if overrides != nil {
+		overridesObj, err := jsonObject(overrides)
+		if err != nil {
+			return nil, err
+		}
+		obj = jsonMerge(obj, overridesObj)
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec PlatformConfig
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
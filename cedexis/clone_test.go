@@ -0,0 +1,51 @@
+package cedexis_test
+
+import (
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestClonePrivatePlatform(t *testing.T) {
+	_, c := newTestClient(t)
+
+	spec := cedexis.NewPrivatePlatform("web", "Web", "Web servers", []string{"prod"})
+	spec.SonarConfig, _ = cedexis.NewSonar().URL("https://web.example.com/health").Build()
+	source, err := c.CreatePrivatePlatform(spec)
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	sources := []int{*source.ID}
+	if err := c.UpdatePrivatePlatform(&cedexis.PlatformConfig{ID: source.ID, Name: source.Name, DisplayName: source.DisplayName,
+		SonarConfig: source.SonarConfig, PlatformSubstitutionSources: &sources}); err != nil {
+		t.Fatalf("UpdatePrivatePlatform failed: %v", err)
+	}
+
+	name, displayName := "web2", "Web 2"
+	clone, err := c.ClonePrivatePlatform(*source.ID, &cedexis.PlatformConfig{Name: &name, DisplayName: &displayName})
+	if err != nil {
+		t.Fatalf("ClonePrivatePlatform failed: %v", err)
+	}
+	if *clone.ID == *source.ID || *clone.Name != name || *clone.SonarConfig.URL != "https://web.example.com/health" ||
+		clone.PlatformSubstitutionSources == nil {
+		t.Errorf("Incorrect clone: %+v", clone)
+	}
+
+	// Without new names the clone clashes with its source
+	if _, err := c.ClonePrivatePlatform(*source.ID, nil); err == nil {
+		t.Errorf("Expected error cloning without overrides")
+	}
+
+	_, other := newTestClient(t)
+	copied, err := c.ClonePrivatePlatformTo(other, *source.ID, nil)
+	if err != nil {
+		t.Fatalf("ClonePrivatePlatformTo failed: %v", err)
+	}
+	if *copied.Name != "web" || copied.PlatformSubstitutionSources != nil {
+		t.Errorf("Incorrect cross-account clone: %+v", copied)
+	}
+	if _, err := other.GetPrivatePlatformByName("web"); err != nil {
+		t.Errorf("Clone not found in other account: %v", err)
+	}
+}
@@ -1,6 +1,10 @@
 package cedexis
 
-import "sort"
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
 
 func stringsDiffer(a *string, b *string) bool {
 	if a == nil {
@@ -123,3 +127,54 @@ func intArraysDiffer(a *[]int, b *[]int) bool {
 
 	return false
 }
+
+// jsonObject converts v to its generic JSON form
+func jsonObject(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	err = json.Unmarshal(data, &obj)
+	return obj, err
+}
+
+// jsonChanges returns the fields of patch that differ from current, recursing into objects
+func jsonChanges(current map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for k, v := range patch {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if cur, ok := current[k].(map[string]interface{}); ok {
+				if subChanges := jsonChanges(cur, sub); len(subChanges) > 0 {
+					changes[k] = subChanges
+				}
+				continue
+			}
+		}
+
+		if !reflect.DeepEqual(current[k], v) {
+			changes[k] = v
+		}
+	}
+	return changes
+}
+
+// jsonMerge applies changes to a copy of current, recursing into objects
+func jsonMerge(current map[string]interface{}, changes map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(current))
+	for k, v := range current {
+		merged[k] = v
+	}
+
+	for k, v := range changes {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if cur, ok := merged[k].(map[string]interface{}); ok {
+				merged[k] = jsonMerge(cur, sub)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
@@ -1,5 +1,7 @@
 package cedexis
 
+import "context"
+
 const countriesReportPath = "/reporting/countries.json"
 const countriesSimpleReportPath = "/reporting/countries.json/simple"
 
@@ -23,34 +25,52 @@ type Country struct {
 
 // GetCountries gets all details for countries.
 func (c *Client) GetCountries() ([]*Country, error) {
-	var resp []*Country
+	return c.GetCountriesWithContext(context.Background())
+}
 
-	if len(c.countriesCache) == 0 {
-		err := c.getJSON(baseURL+countriesReportPath, &resp)
+// GetCountriesWithContext is GetCountries with a context for cancellation and deadlines.
+func (c *Client) GetCountriesWithContext(ctx context.Context) (_ []*Country, err error) {
+	ctx, span := c.startSpan(ctx, "GetCountries")
+	defer func() { endSpan(span, err) }()
 
-		if err != nil {
-			return nil, err
-		}
+	var resp []*Country
 
-		c.countriesCache = map[int]*Country{}
-		for _, a := range resp {
-			c.countriesCache[a.ID] = a
+	if cached, ok := c.cache.list(CacheCountries); ok {
+		resp = make([]*Country, 0, len(cached))
+		for _, a := range cached {
+			resp = append(resp, a.(*Country))
 		}
 
 		return resp, nil
 	}
 
-	resp = make([]*Country, 0, len(c.countriesCache))
-	for _, a := range c.countriesCache {
-		resp = append(resp, a)
+	err = c.getJSON(ctx, c.baseURL+countriesReportPath, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(resp))
+	values := make([]interface{}, 0, len(resp))
+	for _, a := range resp {
+		ids = append(ids, a.ID)
+		values = append(values, a)
 	}
+	c.cache.putAll(CacheCountries, ids, values)
 
 	return resp, nil
 }
 
 // GetCountryByName gets a country by name.
 func (c *Client) GetCountryByName(name string) (*Country, error) {
-	countries, err := c.GetCountries()
+	return c.GetCountryByNameWithContext(context.Background(), name)
+}
+
+// GetCountryByNameWithContext is GetCountryByName with a context for cancellation and deadlines.
+func (c *Client) GetCountryByNameWithContext(ctx context.Context, name string) (_ *Country, err error) {
+	ctx, span := c.startSpan(ctx, "GetCountryByName")
+	defer func() { endSpan(span, err) }()
+
+	countries, err := c.GetCountriesWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,266 @@
+package cedexis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// EnvKeyName is the environment variable holding the API client ID
+	EnvKeyName = "CEDEXIS_KEY_NAME"
+
+	// EnvKeySecret is the environment variable holding the API client secret
+	EnvKeySecret = "CEDEXIS_KEY_SECRET"
+
+	// EnvCredentialsFile overrides the location of the profiles file
+	EnvCredentialsFile = "CEDEXIS_CREDENTIALS_FILE"
+
+	// DefaultProfile is the profile used when none is specified
+	DefaultProfile = "default"
+)
+
+// Credentials are the OAuth client credentials for a Cedexis account
+type Credentials struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// CredentialsProvider supplies credentials for a Cedexis account
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// NewClientWithCredentials creates a new Cedexis API client, with credentials from a provider
+func NewClientWithCredentials(ctx context.Context, p CredentialsProvider, opts ...ClientOption) (*Client, error) {
+	creds, err := p.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientWithOptions(ctx, creds.ClientID, creds.ClientSecret, opts...), nil
+}
+
+// EnvCredentials reads credentials from environment variables
+type EnvCredentials struct {
+	IDVar     string
+	SecretVar string
+}
+
+// NewEnvCredentials reads credentials from CEDEXIS_KEY_NAME and CEDEXIS_KEY_SECRET
+func NewEnvCredentials() *EnvCredentials {
+	return &EnvCredentials{IDVar: EnvKeyName, SecretVar: EnvKeySecret}
+}
+
+// Credentials implements CredentialsProvider
+func (e *EnvCredentials) Credentials(ctx context.Context) (*Credentials, error) {
+	creds := &Credentials{ClientID: os.Getenv(e.IDVar), ClientSecret: os.Getenv(e.SecretVar)}
+	if creds.ClientID == "" || creds.ClientSecret == "" {
+		return nil, fmt.Errorf("Credentials not set in environment variables %s and %s", e.IDVar, e.SecretVar)
+	}
+
+	return creds, nil
+}
+
+// ProfileCredentials reads credentials for a named profile from a profiles file.
+//
+// The file is INI-style:
+//
+//	[prod]
+//	client_id = my-id
+//	client_secret = my-secret
+//
+//	[staging]
+//	credential_process = /usr/local/bin/cedexis-creds --account staging
+//
+// or the equivalent (flat) YAML:
+//
+//	prod:
+//	  client_id: my-id
+//	  client_secret: my-secret
+//
+// A profile with credential_process runs the command as for ExecCredentials.
+type ProfileCredentials struct {
+	Path    string
+	Profile string
+}
+
+// NewProfileCredentials reads a profile from DefaultCredentialsFile
+func NewProfileCredentials(profile string) *ProfileCredentials {
+	if profile == "" {
+		profile = DefaultProfile
+	}
+
+	return &ProfileCredentials{Path: DefaultCredentialsFile(), Profile: profile}
+}
+
+// DefaultCredentialsFile is $CEDEXIS_CREDENTIALS_FILE, or else ~/.cedexis/credentials
+func DefaultCredentialsFile() string {
+	if path := os.Getenv(EnvCredentialsFile); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".cedexis", "credentials")
+	}
+
+	return filepath.Join(home, ".cedexis", "credentials")
+}
+
+// Credentials implements CredentialsProvider
+func (p *ProfileCredentials) Credentials(ctx context.Context) (*Credentials, error) {
+	profiles, err := LoadProfiles(p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[p.Profile]
+	if !ok {
+		return nil, fmt.Errorf("Profile '%s' not found in '%s'", p.Profile, p.Path)
+	}
+
+	if process := profile["credential_process"]; process != "" {
+		fields := strings.Fields(process)
+		return (&ExecCredentials{Command: fields[0], Args: fields[1:]}).Credentials(ctx)
+	}
+
+	creds := &Credentials{ClientID: profile["client_id"], ClientSecret: profile["client_secret"]}
+	if creds.ClientID == "" || creds.ClientSecret == "" {
+		return nil, fmt.Errorf("Profile '%s' in '%s' requires client_id and client_secret", p.Profile, p.Path)
+	}
+
+	return creds, nil
+}
+
+// LoadProfiles reads all profiles from a profiles file (see ProfileCredentials), as a map of
+// profile name to settings
+func LoadProfiles(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	profiles, err := parseProfiles(f)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid profiles file '%s': %v", path, err)
+	}
+
+	return profiles, nil
+}
+
+// parseProfiles parses INI sections or top-level YAML keys into profiles
+func parseProfiles(r io.Reader) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var current map[string]string
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") || line == "---" {
+			continue
+		}
+
+		indented := raw[0] == ' ' || raw[0] == '\t'
+
+		// A YAML header is 'name:', a ':' or '=' before the end separates a key from its value
+		sep := strings.IndexAny(line, "=:")
+
+		var name string
+		switch {
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name = strings.TrimSpace(line[1 : len(line)-1])
+		case !indented && sep == len(line)-1 && line[sep] == ':':
+			name = strings.TrimSpace(line[:sep])
+		}
+
+		if name != "" {
+			current = map[string]string{}
+			profiles[name] = current
+			continue
+		}
+
+		if sep < 0 || current == nil {
+			return nil, fmt.Errorf("line %d: expected profile or 'key = value'", lineNum)
+		}
+
+		key := strings.TrimSpace(line[:sep])
+		value := strings.Trim(strings.TrimSpace(line[sep+1:]), `"'`)
+		current[key] = value
+	}
+
+	return profiles, scanner.Err()
+}
+
+// ExecCredentials runs an external command that prints credentials as JSON, e.g.
+// {"clientId": "...", "clientSecret": "..."}
+type ExecCredentials struct {
+	Command string
+	Args    []string
+}
+
+// Credentials implements CredentialsProvider
+func (e *ExecCredentials) Credentials(ctx context.Context) (*Credentials, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Command, e.Args...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("Credential helper '%s' failed: %v %s", e.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var resp struct {
+		Credentials
+		AltClientID     string `json:"client_id"`
+		AltClientSecret string `json:"client_secret"`
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("Credential helper '%s' returned invalid JSON: %v", e.Command, err)
+	}
+
+	creds := resp.Credentials
+	if creds.ClientID == "" {
+		creds.ClientID = resp.AltClientID
+	}
+	if creds.ClientSecret == "" {
+		creds.ClientSecret = resp.AltClientSecret
+	}
+
+	if creds.ClientID == "" || creds.ClientSecret == "" {
+		return nil, fmt.Errorf("Credential helper '%s' did not return clientId and clientSecret", e.Command)
+	}
+
+	return &creds, nil
+}
+
+// ChainCredentials tries each provider in turn, returning the first credentials found
+type ChainCredentials []CredentialsProvider
+
+// Credentials implements CredentialsProvider
+func (c ChainCredentials) Credentials(ctx context.Context) (*Credentials, error) {
+	var errs []string
+	for _, p := range c {
+		creds, err := p.Credentials(ctx)
+		if err == nil {
+			return creds, nil
+		}
+		errs = append(errs, err.Error())
+	}
+
+	return nil, fmt.Errorf("No credentials found: %s", strings.Join(errs, "; "))
+}
+
+// DefaultCredentials uses the environment if set, or else the default profile
+func DefaultCredentials() CredentialsProvider {
+	return ChainCredentials{NewEnvCredentials(), NewProfileCredentials(DefaultProfile)}
+}
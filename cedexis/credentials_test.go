@@ -0,0 +1,119 @@
+package cedexis
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProfiles(t *testing.T) {
+	tests := map[string]string{
+		"ini": `
+# comment
+[default]
+client_id = dev-id
+client_secret = "dev-secret"
+
+[prod]
+client_id=prod-id
+client_secret=prod-secret
+`,
+		"yaml": `
+---
+default:
+  client_id: dev-id
+  client_secret: 'dev-secret'
+prod:
+  client_id: prod-id
+  client_secret: prod-secret
+`,
+	}
+
+	for name, data := range tests {
+		profiles, err := parseProfiles(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: parseProfiles failed: %v", name, err)
+		}
+
+		if len(profiles) != 2 || profiles["default"]["client_secret"] != "dev-secret" || profiles["prod"]["client_id"] != "prod-id" {
+			t.Errorf("%s: incorrect profiles: %v", name, profiles)
+		}
+	}
+
+	// A value ending in ':' is not a profile header
+	profiles, err := parseProfiles(strings.NewReader("[dev]\nbase_url=https://host:\nclient_id = dev-id\n"))
+	if err != nil || len(profiles) != 1 || profiles["dev"]["base_url"] != "https://host:" || profiles["dev"]["client_id"] != "dev-id" {
+		t.Errorf("Value ending in ':' got (%v, %v)", profiles, err)
+	}
+
+	// Nor is an unindented YAML value ending in ':'
+	profiles, err = parseProfiles(strings.NewReader("dev:\nsecret: abc:\nurl: http://x:\nclient_id: dev-id\n"))
+	if err != nil || len(profiles) != 1 || profiles["dev"]["secret"] != "abc:" || profiles["dev"]["url"] != "http://x:" ||
+		profiles["dev"]["client_id"] != "dev-id" {
+		t.Errorf("YAML value ending in ':' got (%v, %v)", profiles, err)
+	}
+
+	if _, err := parseProfiles(strings.NewReader("client_id = orphan\n")); err == nil {
+		t.Errorf("Expected error for key outside a profile")
+	}
+}
+
+func TestProfileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	data := `
+[prod]
+client_id = prod-id
+client_secret = prod-secret
+
+[helper]
+credential_process = echo {"client_id":"helper-id","client_secret":"helper-secret"}
+
+[broken]
+client_id = only-id
+`
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+
+	creds, err := (&ProfileCredentials{Path: path, Profile: "prod"}).Credentials(ctx)
+	if err != nil || creds.ClientID != "prod-id" || creds.ClientSecret != "prod-secret" {
+		t.Errorf("prod profile got (%v, %v)", creds, err)
+	}
+
+	creds, err = (&ProfileCredentials{Path: path, Profile: "helper"}).Credentials(ctx)
+	if err != nil || creds.ClientID != "helper-id" || creds.ClientSecret != "helper-secret" {
+		t.Errorf("helper profile got (%v, %v)", creds, err)
+	}
+
+	if _, err := (&ProfileCredentials{Path: path, Profile: "broken"}).Credentials(ctx); err == nil {
+		t.Errorf("Expected error for profile without secret")
+	}
+
+	if _, err := (&ProfileCredentials{Path: path, Profile: "missing"}).Credentials(ctx); err == nil {
+		t.Errorf("Expected error for missing profile")
+	}
+}
+
+func TestChainCredentials(t *testing.T) {
+	t.Setenv(EnvKeyName, "")
+	t.Setenv(EnvKeySecret, "")
+
+	missing := &ProfileCredentials{Path: filepath.Join(t.TempDir(), "none"), Profile: DefaultProfile}
+	chain := ChainCredentials{NewEnvCredentials(), missing}
+
+	if _, err := chain.Credentials(context.Background()); err == nil {
+		t.Errorf("Expected error when no provider has credentials")
+	}
+
+	t.Setenv(EnvKeyName, "env-id")
+	t.Setenv(EnvKeySecret, "env-secret")
+
+	creds, err := chain.Credentials(context.Background())
+	if err != nil || creds.ClientID != "env-id" || creds.ClientSecret != "env-secret" {
+		t.Errorf("Chain got (%v, %v), want environment credentials", creds, err)
+	}
+}
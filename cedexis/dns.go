@@ -1,6 +1,7 @@
 package cedexis
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -226,6 +227,14 @@ func (r *Record) DiffersFrom(other *Record) bool {
 
 // CreateZone creates a new DNS zone, with optional zone file
 func (c *Client) CreateZone(name string, description string, tags []string, importContents *string) (*Zone, error) {
+	return c.CreateZoneWithContext(context.Background(), name, description, tags, importContents)
+}
+
+// CreateZoneWithContext is CreateZone with a context for cancellation and deadlines.
+func (c *Client) CreateZoneWithContext(ctx context.Context, name string, description string, tags []string, importContents *string) (_ *Zone, err error) {
+	ctx, span := c.startSpan(ctx, "CreateZone")
+	defer func() { endSpan(span, err) }()
+
 	t := true
 	tagsString := strings.Join(tags, ",")
 
@@ -237,78 +246,106 @@ func (c *Client) CreateZone(name string, description string, tags []string, impo
 		IsPrimary:      &t,
 	}
 
-	err := c.postJSON(baseURL+dnsConfigPath, zone, zone)
+	err = c.postJSON(ctx, c.baseURL+dnsConfigPath, zone, zone)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(c.zoneCache) > 0 {
-		c.zoneCache[*zone.ID] = zone
-	}
+	c.cacheChange(CacheZones, *zone.ID, zone)
 
 	return zone, nil
 }
 
 // GetZones returns all configured zones.
 func (c *Client) GetZones() ([]*Zone, error) {
-	var resp []*Zone
+	return c.GetZonesWithContext(context.Background())
+}
 
-	if len(c.zoneCache) == 0 {
-		err := c.getJSON(baseURL+dnsConfigPath, &resp)
+// GetZonesWithContext is GetZones with a context for cancellation and deadlines.
+func (c *Client) GetZonesWithContext(ctx context.Context) (_ []*Zone, err error) {
+	ctx, span := c.startSpan(ctx, "GetZones")
+	defer func() { endSpan(span, err) }()
 
-		if err != nil {
-			return nil, err
-		}
+	var resp []*Zone
 
-		c.zoneCache = map[int]*Zone{}
-		for _, z := range resp {
-			c.zoneCache[*z.ID] = z
+	if cached, ok := c.cache.list(CacheZones); ok {
+		resp = make([]*Zone, 0, len(cached))
+		for _, z := range cached {
+			resp = append(resp, z.(*Zone))
 		}
 
-	} else {
-		resp = make([]*Zone, 0, len(c.zoneCache))
-		for _, z := range c.zoneCache {
-			resp = append(resp, z)
-		}
+		return resp, nil
 	}
 
+	err = c.getJSON(ctx, c.baseURL+dnsConfigPath, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(resp))
+	values := make([]interface{}, 0, len(resp))
+	for _, z := range resp {
+		ids = append(ids, *z.ID)
+		values = append(values, z)
+	}
+	c.cache.putAll(CacheZones, ids, values)
+
 	return resp, nil
 }
 
 // GetZone gets a zone.
 func (c *Client) GetZone(id int) (*Zone, error) {
-	var result *Zone
+	return c.GetZoneWithContext(context.Background(), id)
+}
+
+// GetZoneWithContext is GetZone with a context for cancellation and deadlines.
+func (c *Client) GetZoneWithContext(ctx context.Context, id int) (_ *Zone, err error) {
+	ctx, span := c.startSpan(ctx, "GetZone")
+	defer func() { endSpan(span, err) }()
 
-	result = c.zoneCache[id]
-	if result != nil {
-		return result, nil
+	if cached, ok := c.cache.get(CacheZones, id); ok {
+		return cached.(*Zone), nil
 	}
 
-	result = &Zone{}
-	err := c.getJSON(baseURL+dnsConfigPath+fmt.Sprintf("/%d", id), result)
+	result := &Zone{}
+	err = c.getJSON(ctx, c.baseURL+dnsConfigPath+fmt.Sprintf("/%d", id), result)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(c.zoneCache) > 0 {
-		c.zoneCache[*result.ID] = result
-	}
+	c.cache.put(CacheZones, *result.ID, result)
 
 	return result, err
 }
 
 // DeleteZone deletes an alert.
 func (c *Client) DeleteZone(id int) error {
-	err := c.delete(baseURL + dnsConfigPath + fmt.Sprintf("/%d", id))
-	if err != nil {
-		delete(c.zoneCache, id)
+	return c.DeleteZoneWithContext(context.Background(), id)
+}
+
+// DeleteZoneWithContext is DeleteZone with a context for cancellation and deadlines.
+func (c *Client) DeleteZoneWithContext(ctx context.Context, id int) (err error) {
+	ctx, span := c.startSpan(ctx, "DeleteZone")
+	defer func() { endSpan(span, err) }()
+
+	err = c.delete(ctx, c.baseURL+dnsConfigPath+fmt.Sprintf("/%d", id))
+	if err == nil {
+		c.cache.remove(CacheZones, id)
 	}
 	return err
 }
 
 // GetZoneByName gets a zone by name, returning nil if not found
 func (c *Client) GetZoneByName(name string) (*Zone, error) {
-	zones, err := c.GetZones()
+	return c.GetZoneByNameWithContext(context.Background(), name)
+}
+
+// GetZoneByNameWithContext is GetZoneByName with a context for cancellation and deadlines.
+func (c *Client) GetZoneByNameWithContext(ctx context.Context, name string) (_ *Zone, err error) {
+	ctx, span := c.startSpan(ctx, "GetZoneByName")
+	defer func() { endSpan(span, err) }()
+
+	zones, err := c.GetZonesWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -325,23 +362,39 @@ func (c *Client) GetZoneByName(name string) (*Zone, error) {
 
 // CreateRecord creates a DNS record
 func (c *Client) CreateRecord(r *Record) (*Record, error) {
+	return c.CreateRecordWithContext(context.Background(), r)
+}
+
+// CreateRecordWithContext is CreateRecord with a context for cancellation and deadlines.
+func (c *Client) CreateRecordWithContext(ctx context.Context, r *Record) (_ *Record, err error) {
+	ctx, span := c.startSpan(ctx, "CreateRecord")
+	defer func() { endSpan(span, err) }()
+
 	out := Record{}
-	err := c.postJSON(baseURL+dnsRecordConfigPath, r, &out)
+	err = c.postJSON(ctx, c.baseURL+dnsRecordConfigPath, r, &out)
 	if err != nil {
 		return nil, err
 	}
 
-	if c.zoneCache[*r.DNSZoneID] != nil {
-		c.zoneCache[*r.DNSZoneID].Records = append(c.zoneCache[*r.DNSZoneID].Records, out)
-	}
+	c.updateCachedRecords(*r.DNSZoneID, func(records []Record) []Record {
+		return append(records, out)
+	})
 
 	return &out, nil
 }
 
 // GetRecord gets a DNS record
 func (c *Client) GetRecord(id int) (*Record, error) {
+	return c.GetRecordWithContext(context.Background(), id)
+}
+
+// GetRecordWithContext is GetRecord with a context for cancellation and deadlines.
+func (c *Client) GetRecordWithContext(ctx context.Context, id int) (_ *Record, err error) {
+	ctx, span := c.startSpan(ctx, "GetRecord")
+	defer func() { endSpan(span, err) }()
+
 	out := Record{}
-	err := c.getJSON(baseURL+dnsRecordConfigPath+fmt.Sprintf("/%d", id), &out)
+	err = c.getJSON(ctx, c.baseURL+dnsRecordConfigPath+fmt.Sprintf("/%d", id), &out)
 	if err != nil {
 		return nil, err
 	}
@@ -351,7 +404,15 @@ func (c *Client) GetRecord(id int) (*Record, error) {
 
 // GetRecordByName gets a DNS record
 func (c *Client) GetRecordByName(zone string, name string, rtype string) (*Record, error) {
-	z, err := c.GetZoneByName(zone)
+	return c.GetRecordByNameWithContext(context.Background(), zone, name, rtype)
+}
+
+// GetRecordByNameWithContext is GetRecordByName with a context for cancellation and deadlines.
+func (c *Client) GetRecordByNameWithContext(ctx context.Context, zone string, name string, rtype string) (_ *Record, err error) {
+	ctx, span := c.startSpan(ctx, "GetRecordByName")
+	defer func() { endSpan(span, err) }()
+
+	z, err := c.GetZoneByNameWithContext(ctx, zone)
 	if err != nil {
 		return nil, err
 	}
@@ -373,21 +434,52 @@ func (c *Client) GetRecordByName(zone string, name string, rtype string) (*Recor
 
 // UpdateRecord updates a record
 func (c *Client) UpdateRecord(r *Record) (*Record, error) {
-	err := c.putJSON(baseURL+dnsRecordConfigPath+fmt.Sprintf("/%d", *r.ID), r, nil)
+	return c.UpdateRecordWithContext(context.Background(), r)
+}
+
+// UpdateRecordWithContext is UpdateRecord with a context for cancellation and deadlines.
+func (c *Client) UpdateRecordWithContext(ctx context.Context, r *Record) (_ *Record, err error) {
+	ctx, span := c.startSpan(ctx, "UpdateRecord")
+	defer func() { endSpan(span, err) }()
+
+	err = c.putJSON(ctx, c.baseURL+dnsRecordConfigPath+fmt.Sprintf("/%d", *r.ID), r, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	out, err := c.GetRecord(*r.ID)
-	if err == nil {
-		if c.zoneCache[*r.DNSZoneID] != nil {
-			for i, existing := range c.zoneCache[*r.DNSZoneID].Records {
-				if existing.ID == r.ID {
-					c.zoneCache[*r.DNSZoneID].Records[i] = *out
-				}
+	// Nothing changed to read back in a dry run
+	out := r
+	if c.changeLog == nil {
+		out, err = c.GetRecordWithContext(ctx, *r.ID)
+		if err != nil {
+			c.cache.invalidate(CacheZones, *r.DNSZoneID)
+			return nil, err
+		}
+	}
+
+	c.updateCachedRecords(*r.DNSZoneID, func(records []Record) []Record {
+		for i, existing := range records {
+			if existing.ID != nil && *existing.ID == *r.ID {
+				records[i] = *out
 			}
 		}
+		return records
+	})
+
+	return out, nil
+}
+
+// updateCachedRecords applies a change to the records of a cached zone.  The zone is copied, since
+// callers may hold the previously cached value.
+func (c *Client) updateCachedRecords(zoneID int, fn func(records []Record) []Record) {
+	if c.changeLog != nil {
+		c.cache.invalidate(CacheZones, zoneID)
+		return
 	}
 
-	return out, err
+	c.cache.update(CacheZones, zoneID, func(v interface{}) interface{} {
+		zone := *v.(*Zone)
+		zone.Records = fn(append([]Record(nil), zone.Records...))
+		return &zone
+	})
 }
@@ -0,0 +1,102 @@
+package cedexis
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Change is a mutating request recorded, instead of sent, by a dry-run client
+type Change struct {
+	Time   time.Time
+	Method string
+	URL    string
+	Body   string
+
+	// ID is the fabricated ID given to a created object, or nil
+	ID *int
+}
+
+// ChangeLog records the changes a dry-run client would have made.  Objects created during a dry run
+// get fabricated, negative, IDs so they can't be mistaken for real ones.
+type ChangeLog struct {
+	mu      sync.Mutex
+	changes []Change
+	lastID  int
+}
+
+// WithDryRun records POST, PUT, PATCH and DELETE requests in a ChangeLog (see Client.ChangeLog)
+// instead of sending them, and reports success.  Reads are still sent to Cedexis.
+func WithDryRun() ClientOption {
+	return func(o *clientOptions) {
+		o.changeLog = NewChangeLog()
+	}
+}
+
+// WithChangeLog is WithDryRun recording into an existing log, which may be shared with other clients
+func WithChangeLog(l *ChangeLog) ClientOption {
+	return func(o *clientOptions) {
+		o.changeLog = l
+	}
+}
+
+// NewChangeLog creates an empty ChangeLog
+func NewChangeLog() *ChangeLog {
+	return &ChangeLog{}
+}
+
+// Changes returns the recorded changes, oldest first
+func (l *ChangeLog) Changes() []Change {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]Change(nil), l.changes...)
+}
+
+// Reset discards the recorded changes
+func (l *ChangeLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.changes = nil
+}
+
+func (l *ChangeLog) record(method string, url string, body []byte) *Change {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	change := Change{Time: time.Now(), Method: method, URL: url, Body: string(body)}
+	if method == "POST" {
+		l.lastID--
+		id := l.lastID
+		change.ID = &id
+	}
+
+	l.changes = append(l.changes, change)
+	return &change
+}
+
+// ChangeLog returns the log of a dry-run client, or nil if requests are sent
+func (c *Client) ChangeLog() *ChangeLog {
+	return c.changeLog
+}
+
+// dryRun records a mutating request, and fills recv with the synthetic response: the sent object,
+// with a fabricated ID if it was created
+func (c *Client) dryRun(method string, url string, data []byte, recv interface{}) error {
+	change := c.changeLog.record(method, url, data)
+
+	if recv == nil || len(data) == 0 {
+		return nil
+	}
+
+	if change.ID != nil {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(data, &obj); err == nil && obj["id"] == nil {
+			obj["id"] = *change.ID
+			data, _ = json.Marshal(obj)
+		}
+	}
+
+	return json.Unmarshal(data, recv)
+}
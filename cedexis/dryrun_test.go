@@ -0,0 +1,93 @@
+package cedexis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestDryRun(t *testing.T) {
+	srv, c := newTestClient(t, cedexis.WithDryRun())
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+	if p.ID == nil || *p.ID >= 0 || *p.Name != "web" {
+		t.Errorf("Got platform %+v, want fabricated negative ID", p)
+	}
+
+	a, err := c.CreateAlert(c.NewAlert("web-down", cedexis.AlertTypeSonar, *p.ID, cedexis.AlertChangeToDown,
+		cedexis.AlertTimingImmediate, nil, 300))
+	if err != nil || a.ID == nil || *a.ID == *p.ID {
+		t.Errorf("CreateAlert got (%v, %v), want a distinct fabricated ID", a, err)
+	}
+
+	threshold := 2
+	a.Threshold = &threshold
+	if updated, err := c.UpdateAlert(a); err != nil || *updated.Threshold != threshold {
+		t.Errorf("UpdateAlert got (%v, %v), want the updated alert", updated, err)
+	}
+
+	if err := c.DeletePrivatePlatform(*p.ID); err != nil {
+		t.Errorf("DeletePrivatePlatform failed: %v", err)
+	}
+
+	// Reads are still sent
+	if _, err := c.GetZones(); err != nil {
+		t.Errorf("GetZones failed: %v", err)
+	}
+
+	for _, r := range srv.Requests() {
+		if r.Method != "GET" {
+			t.Errorf("Dry run sent %s %s", r.Method, r.Path)
+		}
+	}
+
+	changes := c.ChangeLog().Changes()
+	methods := []string{"POST", "POST", "PUT", "DELETE"}
+	if len(changes) != len(methods) {
+		t.Fatalf("Got %d changes, want %d", len(changes), len(methods))
+	}
+	for i, m := range methods {
+		if changes[i].Method != m {
+			t.Errorf("Change %d: got %s, want %s", i, changes[i].Method, m)
+		}
+	}
+	if !strings.Contains(changes[0].Body, `"name":"web"`) || changes[0].ID == nil || *changes[0].ID != *p.ID {
+		t.Errorf("Incorrect change recorded for create: %+v", changes[0])
+	}
+
+	c.ChangeLog().Reset()
+	if n := len(c.ChangeLog().Changes()); n != 0 {
+		t.Errorf("Got %d changes after reset, want 0", n)
+	}
+}
+
+func TestDryRunUpdateNotCached(t *testing.T) {
+	srv, c := newTestClient(t, cedexis.WithDryRun())
+
+	added, err := srv.AddPrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("AddPrivatePlatform failed: %v", err)
+	}
+
+	p, err := c.GetPrivatePlatform(*added.ID)
+	if err != nil {
+		t.Fatalf("GetPrivatePlatform failed: %v", err)
+	}
+	name := "renamed"
+	p.Name = &name
+	if err := c.UpdatePrivatePlatform(p); err != nil {
+		t.Fatalf("UpdatePrivatePlatform failed: %v", err)
+	}
+
+	p, err = c.GetPrivatePlatform(*added.ID)
+	if err != nil {
+		t.Fatalf("GetPrivatePlatform failed: %v", err)
+	}
+	if *p.Name != "web" {
+		t.Errorf("Got name '%s' after a dry-run update, want 'web'", *p.Name)
+	}
+}
@@ -0,0 +1,75 @@
+package cedexis
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorDetail is a single failure reason reported by the Cedexis API
+type ErrorDetail struct {
+	DeveloperMessage string `json:"developerMessage"`
+	UserMessage      string `json:"userMessage"`
+	Field            string `json:"field"`
+	ErrorCode        string `json:"errorCode"`
+	MoreInfo         string `json:"moreInfo"`
+	RootCause        string `json:"rootCause"`
+}
+
+type cedexisErrorResponse struct {
+	HTTPStatus   int           `json:"httpStatus"`
+	ErrorDetails []ErrorDetail `json:"errorDetails"`
+}
+
+// APIError is returned when the Cedexis API responds with a failure status.  Use errors.As
+// to recover it from errors returned by Client methods.
+type APIError struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+
+	// Method and URL identify the failed request
+	Method string
+	URL    string
+
+	// Details holds the reasons reported by Cedexis, empty if the body could not be parsed
+	Details []ErrorDetail
+}
+
+func (e *APIError) Error() string {
+	for _, d := range e.Details {
+		if d.UserMessage != "" {
+			return fmt.Sprintf("Call to Cedexis failed, because '%s'", d.UserMessage)
+		}
+	}
+
+	return fmt.Sprintf("Call to Cedexis failed, error code %v", e.StatusCode)
+}
+
+// IsNotFound indicates the error is due to a missing resource
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict indicates the error is due to a conflict with existing state (e.g. a duplicate name)
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsValidation indicates Cedexis rejected the content of the request
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest) || hasStatus(err, http.StatusUnprocessableEntity)
+}
+
+// IsRateLimited indicates the request was refused due to rate limiting
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == status
+	}
+
+	return false
+}
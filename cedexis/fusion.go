@@ -0,0 +1,126 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const fusionArchetypesPath = "/config/platforms.json/fusionArchetypes"
+
+const (
+	// MinFusionLoadRate is the shortest time allowed between Fusion loads
+	MinFusionLoadRate = time.Minute
+
+	// MaxFusionLoadRate is the longest time allowed between Fusion loads
+	MaxFusionLoadRate = 24 * time.Hour
+)
+
+// FusionArchetype is a kind of Fusion data feed that can supply a private platform
+type FusionArchetype struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetFusionArchetypes gets the Fusion data feeds available to private platforms.  The response
+// schema is unverified against the live API.
+func (c *Client) GetFusionArchetypes() ([]*FusionArchetype, error) {
+	return c.GetFusionArchetypesWithContext(context.Background())
+}
+
+// GetFusionArchetypesWithContext is GetFusionArchetypes with a context for cancellation and deadlines.
+func (c *Client) GetFusionArchetypesWithContext(ctx context.Context) (_ []*FusionArchetype, err error) {
+	ctx, span := c.startSpan(ctx, "GetFusionArchetypes")
+	defer func() { endSpan(span, err) }()
+
+	var resp []*FusionArchetype
+	err = c.getJSON(ctx, c.baseURL+fusionArchetypesPath, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Validate checks the load URL, and that the load rate is between MinFusionLoadRate and MaxFusionLoadRate
+func (f *FusionCustomConfig) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.LoadURL != nil {
+		if err := validateURL(*f.LoadURL); err != nil {
+			return fmt.Errorf("Invalid fusion load URL: %v", err)
+		}
+	} else if f.Enabled != nil && *f.Enabled {
+		return fmt.Errorf("Fusion requires load URL to be enabled")
+	}
+
+	if f.LoadRateSeconds != nil {
+		rate := time.Duration(*f.LoadRateSeconds) * time.Second
+		if rate < MinFusionLoadRate || rate > MaxFusionLoadRate {
+			return fmt.Errorf("Fusion load rate %v must be between %v and %v", rate, MinFusionLoadRate, MaxFusionLoadRate)
+		}
+	}
+
+	return nil
+}
+
+// changes is a copy of f with only the fields that differ from current, or f if current is nil.
+// Enabling is kept only if there is no load URL before or after.
+func (f *FusionCustomConfig) changes(current *FusionCustomConfig) *FusionCustomConfig {
+	if f == nil || current == nil {
+		return f
+	}
+
+	changed := &FusionCustomConfig{}
+	if f.LoadURL != nil && stringsDiffer(f.LoadURL, current.LoadURL) {
+		changed.LoadURL = f.LoadURL
+	}
+	if f.LoadRateSeconds != nil && intsDiffer(f.LoadRateSeconds, current.LoadRateSeconds) {
+		changed.LoadRateSeconds = f.LoadRateSeconds
+	}
+	if f.Enabled != nil && boolsDiffer(f.Enabled, current.Enabled) && f.LoadURL == nil && current.LoadURL == nil {
+		changed.Enabled = f.Enabled
+	}
+	return changed
+}
+
+// NewFusionPrivatePlatform simplifies creating a ConfiguredPlatform instance when the platform is fed
+// by Fusion.
+//
+// The parameters are defined as follows:
+//     name        Unique identifier for the platform (no spaces)
+//     displayName Unique friendly name for the platform (may have spaces)
+//     description Description of the new platform
+//     archetype   ID of the Fusion archetype feeding the platform (see GetFusionArchetypes)
+//     loadURL     URL Fusion loads data from, or empty if the archetype doesn't need one
+//     loadRate    Time between loads from loadURL
+//     tags        Tags to apply to the new platform
+//
+func NewFusionPrivatePlatform(
+	name string,
+	displayName string,
+	description string,
+	archetype string,
+	loadURL string,
+	loadRate time.Duration,
+	tags []string) *PlatformConfig {
+
+	p := NewPrivatePlatform(name, displayName, description, tags)
+	p.FusionArchetype = &archetype
+
+	if loadURL != "" {
+		enabled := true
+		loadRateSeconds := int(loadRate / time.Second)
+		p.FusionCustomConfig = &FusionCustomConfig{
+			Enabled:         &enabled,
+			LoadURL:         &loadURL,
+			LoadRateSeconds: &loadRateSeconds,
+		}
+	}
+
+	return p
+}
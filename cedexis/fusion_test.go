@@ -0,0 +1,41 @@
+package cedexis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestFusion(t *testing.T) {
+	srv, c := newTestClient(t)
+	srv.AddFusionArchetype(&cedexis.FusionArchetype{ID: "custom-json", Name: "Custom JSON"})
+
+	archetypes, err := c.GetFusionArchetypes()
+	if err != nil || len(archetypes) != 1 || archetypes[0].ID != "custom-json" {
+		t.Fatalf("GetFusionArchetypes got (%v, %v)", archetypes, err)
+	}
+
+	spec := cedexis.NewFusionPrivatePlatform("feed", "Feed", "", "custom-json", "https://data.example.com/feed.json", 5*time.Minute, nil)
+	p, err := c.CreatePrivatePlatform(spec)
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+	if *p.FusionArchetype != "custom-json" || !*p.FusionCustomConfig.Enabled || *p.FusionCustomConfig.LoadRateSeconds != 300 {
+		t.Errorf("Incorrect fusion platform: %+v", p)
+	}
+
+	invalid := map[string]*cedexis.PlatformConfig{
+		"bad URL":    cedexis.NewFusionPrivatePlatform("bad1", "Bad 1", "", "custom-json", "data.example.com", 5*time.Minute, nil),
+		"rate short": cedexis.NewFusionPrivatePlatform("bad2", "Bad 2", "", "custom-json", "https://data.example.com", time.Second, nil),
+		"rate long":  cedexis.NewFusionPrivatePlatform("bad3", "Bad 3", "", "custom-json", "https://data.example.com", 48*time.Hour, nil),
+	}
+	for name, spec := range invalid {
+		if _, err := c.CreatePrivatePlatform(spec); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := countRequests(srv, "POST", "/config/platforms.json"); n != 1 {
+		t.Errorf("Got %d POST requests, want only the valid platform", n)
+	}
+}
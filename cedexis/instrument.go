@@ -0,0 +1,69 @@
+package cedexis
+
+import (
+	"strings"
+	"time"
+)
+
+// Resources reported to Instrumentation
+const (
+	ResourcePlatform    = "platform"
+	ResourceAlert       = "alert"
+	ResourceZone        = "zone"
+	ResourceRecord      = "record"
+	ResourceApplication = "application"
+	ResourceCountry     = "country"
+	ResourcePing        = "ping"
+	ResourceReport      = "report"
+	ResourceOther       = "other"
+)
+
+// Instrumentation receives events about the HTTP requests made by a Client, e.g. to export
+// metrics (see package cedexisprom).  Resource is one of the Resource* constants, method is the
+// HTTP method.  Implementations must be safe for concurrent use.
+type Instrumentation interface {
+	// RequestDone is called after every attempt, status is 0 if no response was received
+	RequestDone(resource string, method string, status int, latency time.Duration)
+
+	// Retried is called when a failed attempt will be retried after delay.  Status is that of the
+	// failed attempt, 0 if no response was received, and 429 if Cedexis asked the client to slow down.
+	Retried(resource string, method string, status int, delay time.Duration)
+
+	// RateLimited is called when the client's rate limiter delayed a request
+	RateLimited(resource string, method string, wait time.Duration)
+}
+
+// WithInstrumentation reports every request made by the client to i
+func WithInstrumentation(i Instrumentation) ClientOption {
+	return func(o *clientOptions) {
+		o.instrumentation = i
+	}
+}
+
+// resourcePaths maps API paths to resources, more specific paths first
+var resourcePaths = []struct {
+	path     string
+	resource string
+}{
+	{dnsRecordConfigPath, ResourceRecord},
+	{dnsConfigPath, ResourceZone},
+	{platformsConfigPath, ResourcePlatform},
+	{platformsReportingPath, ResourcePlatform},
+	{alertsConfigPath, ResourceAlert},
+	{appsConfigPath, ResourceApplication},
+	{countriesReportPath, ResourceCountry},
+	{radarReportPath, ResourceReport},
+	{sonarReportPath, ResourceReport},
+	{pingPath, ResourcePing},
+}
+
+// resourceOf returns the resource a request URL refers to
+func (c *Client) resourceOf(url string) string {
+	path := strings.TrimPrefix(url, c.baseURL)
+	for _, rp := range resourcePaths {
+		if strings.HasPrefix(path, rp.path) {
+			return rp.resource
+		}
+	}
+	return ResourceOther
+}
@@ -1,5 +1,10 @@
 package cedexis
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Market holds the market as two-character 'ISO codes'
 type Market string
 
@@ -28,3 +33,13 @@ const (
 	// MarketUnknown is a placeholder when the market is unknown
 	MarketUnknown Market = "XX"
 )
+
+// ParseMarket parses a market ISO code, e.g. 'EU', to enum value
+func ParseMarket(val string) (Market, error) {
+	switch m := Market(strings.ToUpper(val)); m {
+	case MarketGlobal, MarketAfrica, MarketAsia, MarketEurope, MarketNorthAmerica, MarketOceania, MarketSouthAmerica:
+		return m, nil
+	default:
+		return "", fmt.Errorf("Invalid market '%s'", val)
+	}
+}
@@ -0,0 +1,256 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MultiClient holds clients for many Cedexis accounts, keyed by account name.  Read calls are
+// fanned out to all accounts in parallel, and failures for some accounts don't prevent results
+// being returned for the others.
+type MultiClient struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// AccountError is the failure of a call to one account of a MultiClient
+type AccountError struct {
+	Account string
+	Err     error
+}
+
+func (e *AccountError) Error() string {
+	return fmt.Sprintf("Account '%s': %v", e.Account, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *AccountError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError aggregates the per-account failures of a MultiClient call
+type MultiError struct {
+	Errors []*AccountError
+}
+
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d account(s) failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the per-account errors, so errors.As and the Is* helpers match any of them
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// AccountPlatforms are the platforms of one account
+type AccountPlatforms struct {
+	Account   string
+	Platforms []*PlatformInfo
+}
+
+// AccountApplications are the Openmix applications of one account
+type AccountApplications struct {
+	Account      string
+	Applications []*Application
+}
+
+// AccountZones are the DNS zones of one account
+type AccountZones struct {
+	Account string
+	Zones   []*Zone
+}
+
+// NewMultiClient creates an empty MultiClient
+func NewMultiClient() *MultiClient {
+	return &MultiClient{clients: map[string]*Client{}}
+}
+
+// NewMultiClientFromProfiles creates a MultiClient with an account for each profile in a
+// profiles file (see ProfileCredentials).  Profiles without valid credentials are reported in a
+// *MultiError, the returned MultiClient holds the remaining accounts.
+func NewMultiClientFromProfiles(ctx context.Context, path string, opts ...ClientOption) (*MultiClient, error) {
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := NewMultiClient()
+	merr := &MultiError{}
+	for name := range profiles {
+		c, err := NewClientWithCredentials(ctx, &ProfileCredentials{Path: path, Profile: name}, opts...)
+		if err != nil {
+			merr.Errors = append(merr.Errors, &AccountError{Account: name, Err: err})
+			continue
+		}
+		m.Add(name, c)
+	}
+
+	return m, merr.errorOrNil()
+}
+
+// Add adds (or replaces) the client for an account
+func (m *MultiClient) Add(account string, c *Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clients[account] = c
+}
+
+// Remove removes an account
+func (m *MultiClient) Remove(account string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.clients, account)
+}
+
+// Client returns the client for an account, or nil if there is no such account
+func (m *MultiClient) Client(account string) *Client {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.clients[account]
+}
+
+// Accounts returns the account names, sorted
+func (m *MultiClient) Accounts() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	accounts := make([]string, 0, len(m.clients))
+	for name := range m.clients {
+		accounts = append(accounts, name)
+	}
+	sort.Strings(accounts)
+
+	return accounts
+}
+
+// Each calls fn for every account in parallel.  Failures are returned as a *MultiError, sorted
+// by account.
+func (m *MultiClient) Each(ctx context.Context, fn func(ctx context.Context, account string, c *Client) error) error {
+	accounts := m.Accounts()
+	errs := make([]error, len(accounts))
+
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		c := m.Client(account)
+		if c == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, account string, c *Client) {
+			defer wg.Done()
+			errs[i] = fn(ctx, account, c)
+		}(i, account, c)
+	}
+	wg.Wait()
+
+	merr := &MultiError{}
+	for i, err := range errs {
+		if err != nil {
+			merr.Errors = append(merr.Errors, &AccountError{Account: accounts[i], Err: err})
+		}
+	}
+
+	return merr.errorOrNil()
+}
+
+// GetPlatforms gets platforms from all accounts
+func (m *MultiClient) GetPlatforms(t PlatformType) ([]*AccountPlatforms, error) {
+	return m.GetPlatformsWithContext(context.Background(), t)
+}
+
+// GetPlatformsWithContext is GetPlatforms with a context for cancellation and deadlines.
+func (m *MultiClient) GetPlatformsWithContext(ctx context.Context, t PlatformType) ([]*AccountPlatforms, error) {
+	var mu sync.Mutex
+	var result []*AccountPlatforms
+
+	err := m.Each(ctx, func(ctx context.Context, account string, c *Client) error {
+		platforms, err := c.GetPlatformsWithContext(ctx, t)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		result = append(result, &AccountPlatforms{Account: account, Platforms: platforms})
+		mu.Unlock()
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Account < result[j].Account })
+	return result, err
+}
+
+// GetApplications gets Openmix applications from all accounts
+func (m *MultiClient) GetApplications() ([]*AccountApplications, error) {
+	return m.GetApplicationsWithContext(context.Background())
+}
+
+// GetApplicationsWithContext is GetApplications with a context for cancellation and deadlines.
+func (m *MultiClient) GetApplicationsWithContext(ctx context.Context) ([]*AccountApplications, error) {
+	var mu sync.Mutex
+	var result []*AccountApplications
+
+	err := m.Each(ctx, func(ctx context.Context, account string, c *Client) error {
+		apps, err := c.GetApplicationsWithContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		result = append(result, &AccountApplications{Account: account, Applications: apps})
+		mu.Unlock()
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Account < result[j].Account })
+	return result, err
+}
+
+// GetZones gets DNS zones from all accounts
+func (m *MultiClient) GetZones() ([]*AccountZones, error) {
+	return m.GetZonesWithContext(context.Background())
+}
+
+// GetZonesWithContext is GetZones with a context for cancellation and deadlines.
+func (m *MultiClient) GetZonesWithContext(ctx context.Context) ([]*AccountZones, error) {
+	var mu sync.Mutex
+	var result []*AccountZones
+
+	err := m.Each(ctx, func(ctx context.Context, account string, c *Client) error {
+		zones, err := c.GetZonesWithContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		result = append(result, &AccountZones{Account: account, Zones: zones})
+		mu.Unlock()
+		return nil
+	})
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Account < result[j].Account })
+	return result, err
+}
+
+func (e *MultiError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	sort.Slice(e.Errors, func(i, j int) bool { return e.Errors[i].Account < e.Errors[j].Account })
+	return e
+}
@@ -0,0 +1,50 @@
+package cedexis_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+	"github.com/ctxkenb/cedexis-golang/cedexis/cedexistest"
+)
+
+func TestMultiClient(t *testing.T) {
+	prodSrv, prod := newTestClient(t)
+	stagingSrv, staging := newTestClient(t)
+	_, dev := newTestClient(t)
+
+	prodSrv.AddPrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	prodSrv.AddPrivatePlatform(cedexis.NewPrivatePlatform("api", "API", "", nil))
+	stagingSrv.InjectFault(cedexistest.Fault{Status: 404})
+
+	m := cedexis.NewMultiClient()
+	m.Add("prod", prod)
+	m.Add("staging", staging)
+	m.Add("dev", dev)
+
+	if accounts := m.Accounts(); len(accounts) != 3 || accounts[0] != "dev" {
+		t.Errorf("Incorrect accounts: %v", accounts)
+	}
+
+	result, err := m.GetPlatforms(cedexis.PlatformsTypePrivate)
+
+	var merr *cedexis.MultiError
+	if !errors.As(err, &merr) || len(merr.Errors) != 1 || merr.Errors[0].Account != "staging" {
+		t.Fatalf("GetPlatforms got error %v, want failure of staging only", err)
+	}
+	if !cedexis.IsNotFound(err) {
+		t.Errorf("Aggregated error should match IsNotFound: %v", err)
+	}
+
+	if len(result) != 2 || result[0].Account != "dev" || result[1].Account != "prod" {
+		t.Fatalf("GetPlatforms got %v, want results for dev and prod", result)
+	}
+	if len(result[0].Platforms) != 0 || len(result[1].Platforms) != 2 {
+		t.Errorf("Incorrect platforms: dev %d, prod %d", len(result[0].Platforms), len(result[1].Platforms))
+	}
+
+	m.Remove("staging")
+	if zones, err := m.GetZones(); err != nil || len(zones) != 2 {
+		t.Errorf("GetZones got (%v, %v), want 2 accounts", zones, err)
+	}
+}
@@ -0,0 +1,76 @@
+package cedexis
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/ctxkenb/cedexis-golang/cedexis"
+
+// WithTracerProvider sets the OpenTelemetry TracerProvider used for spans, by default the global
+// provider (otel.GetTracerProvider)
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(o *clientOptions) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithPropagator sets how trace context is added to requests, by default W3C trace context
+func WithPropagator(p propagation.TextMapPropagator) ClientOption {
+	return func(o *clientOptions) {
+		o.propagator = p
+	}
+}
+
+// startSpan opens the span for a public method, which is the parent of its HTTP attempts
+func (c *Client) startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, "cedexis."+method)
+}
+
+// endSpan records any error and ends a span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// startAttemptSpan opens a client span for one HTTP attempt, and adds its trace context to req
+func (c *Client) startAttemptSpan(req *http.Request, resource string, attempt int) (*http.Request, trace.Span) {
+	ctx, span := c.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("cedexis.resource", resource),
+			attribute.Int("http.request.resend_count", attempt-1),
+		))
+
+	req = req.WithContext(ctx)
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req, span
+}
+
+// endAttemptSpan records the outcome of an HTTP attempt and ends its span
+func endAttemptSpan(span trace.Span, resp *http.Response, err error) {
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if err == nil && resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+	endSpan(span, err)
+}
+
+func defaultTracerProvider() trace.TracerProvider {
+	return otel.GetTracerProvider()
+}
@@ -0,0 +1,105 @@
+package cedexis_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+	"github.com/ctxkenb/cedexis-golang/cedexis/cedexistest"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// headerRecorder captures the traceparent header of API requests
+type headerRecorder struct {
+	mu           sync.Mutex
+	traceparents []string
+}
+
+func (h *headerRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.Contains(req.URL.Path, "/api/v2/") {
+		h.mu.Lock()
+		h.traceparents = append(h.traceparents, req.Header.Get("traceparent"))
+		h.mu.Unlock()
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	headers := &headerRecorder{}
+
+	srv, c := newTestClient(t, cedexis.WithTracerProvider(tp), cedexis.WithTransport(headers))
+
+	srv.InjectFault(cedexistest.Fault{Method: "GET", Path: "/meta", Status: 429, RetryAfter: "0", Times: 1})
+	if err := c.Ping(); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 3 {
+		t.Fatalf("Got %d spans, want 2 attempts and the Ping span", len(ended))
+	}
+
+	parent := ended[2]
+	if parent.Name() != "cedexis.Ping" {
+		t.Fatalf("Got parent span %q, want cedexis.Ping", parent.Name())
+	}
+
+	for i, attempt := range ended[:2] {
+		if attempt.Name() != "HTTP GET" || attempt.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Attempt %d: got span %q with parent %v, want HTTP GET child of Ping", i, attempt.Name(), attempt.Parent().SpanID())
+		}
+
+		want := attempt.SpanContext().TraceID().String() + "-" + attempt.SpanContext().SpanID().String()
+		if i >= len(headers.traceparents) || !strings.Contains(headers.traceparents[i], want) {
+			t.Errorf("Attempt %d: request traceparent %v, want to contain %s", i, headers.traceparents, want)
+		}
+	}
+
+	if ended[0].Status().Code != codes.Error || ended[1].Status().Code == codes.Error {
+		t.Errorf("Got attempt statuses %v, %v, want error then success", ended[0].Status(), ended[1].Status())
+	}
+
+	srv.InjectFault(cedexistest.Fault{Status: 404, Times: 1})
+	c.PingWithContext(context.Background())
+
+	ended = spans.Ended()
+	if last := ended[len(ended)-1]; last.Name() != "cedexis.Ping" || last.Status().Code != codes.Error {
+		t.Errorf("Got span %q with status %v, want failed cedexis.Ping", last.Name(), last.Status())
+	}
+}
+
+func TestEnablePlatformTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	_, c := newTestClient(t, cedexis.WithTracerProvider(tp))
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	for _, op := range []struct {
+		span string
+		fn   func(int) error
+	}{
+		{"cedexis.DisablePlatform", c.DisablePlatform},
+		{"cedexis.EnablePlatform", c.EnablePlatform},
+	} {
+		if err := op.fn(*p.ID); err != nil {
+			t.Fatalf("%s failed: %v", op.span, err)
+		}
+
+		ended := spans.Ended()
+		if last := ended[len(ended)-1]; last.Name() != op.span {
+			t.Errorf("Got last span %q, want %s", last.Name(), op.span)
+		}
+	}
+}
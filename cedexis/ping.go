@@ -1,6 +1,9 @@
 package cedexis
 
-import "errors"
+import (
+	"context"
+	"errors"
+)
 
 const pingPath = "/meta/system.json/ping"
 
@@ -10,8 +13,16 @@ type pingResponse struct {
 
 // Ping validates connectivity to Cedexis API - returns error on failure
 func (c *Client) Ping() error {
+	return c.PingWithContext(context.Background())
+}
+
+// PingWithContext is Ping with a context for cancellation and deadlines.
+func (c *Client) PingWithContext(ctx context.Context) (err error) {
+	ctx, span := c.startSpan(ctx, "Ping")
+	defer func() { endSpan(span, err) }()
+
 	var resp pingResponse
-	err := c.getJSON(baseURL+pingPath, &resp)
+	err = c.getJSON(ctx, c.baseURL+pingPath, &resp)
 
 	if err != nil {
 		return err
@@ -1,7 +1,10 @@
 package cedexis
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -157,8 +160,16 @@ const (
 
 // GetProviderCategories gets the platform provider categories (CDN, Cloud, etc)
 func (c *Client) GetProviderCategories() ([]*NameID, error) {
+	return c.GetProviderCategoriesWithContext(context.Background())
+}
+
+// GetProviderCategoriesWithContext is GetProviderCategories with a context for cancellation and deadlines.
+func (c *Client) GetProviderCategoriesWithContext(ctx context.Context) (_ []*NameID, err error) {
+	ctx, span := c.startSpan(ctx, "GetProviderCategories")
+	defer func() { endSpan(span, err) }()
+
 	var resp []*NameID
-	err := c.getJSON(baseURL+providerCategoriesPath, &resp)
+	err = c.getJSON(ctx, c.baseURL+providerCategoriesPath, &resp)
 
 	if err != nil {
 		return nil, err
@@ -169,7 +180,15 @@ func (c *Client) GetProviderCategories() ([]*NameID, error) {
 
 // GetPlatforms gets information about all public and private platforms
 func (c *Client) GetPlatforms(t PlatformType) ([]*PlatformInfo, error) {
-	path := baseURL + platformsReportingPath
+	return c.GetPlatformsWithContext(context.Background(), t)
+}
+
+// GetPlatformsWithContext is GetPlatforms with a context for cancellation and deadlines.
+func (c *Client) GetPlatformsWithContext(ctx context.Context, t PlatformType) (_ []*PlatformInfo, err error) {
+	ctx, span := c.startSpan(ctx, "GetPlatforms")
+	defer func() { endSpan(span, err) }()
+
+	path := c.baseURL + platformsReportingPath
 	switch t {
 	case PlatformsTypeCommunity:
 		path += "/community"
@@ -185,63 +204,191 @@ func (c *Client) GetPlatforms(t PlatformType) ([]*PlatformInfo, error) {
 	var resp []*PlatformInfo
 
 	// Try cache read
-	if t == PlatformsTypePrivate && len(c.privatePlatformListCache) > 0 {
-		resp = make([]*PlatformInfo, 0, len(c.privatePlatformListCache))
-		for _, p := range c.privatePlatformListCache {
-			resp = append(resp, p)
+	if t == PlatformsTypePrivate {
+		if cached, ok := c.cache.list(CachePlatformList); ok {
+			resp = make([]*PlatformInfo, 0, len(cached))
+			for _, p := range cached {
+				resp = append(resp, p.(*PlatformInfo))
+			}
+			return resp, nil
 		}
-		return resp, nil
 	}
 
 	// Not cached, go to service
-	err := c.getJSON(path, &resp)
+	err = c.getJSON(ctx, path, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	// Cache
-	c.privatePlatformListCache = map[int]*PlatformInfo{}
-	for _, p := range resp {
-		c.privatePlatformListCache[*p.ID] = p
+	// Cache, only the private list is cached
+	if t == PlatformsTypePrivate {
+		ids := make([]int, 0, len(resp))
+		values := make([]interface{}, 0, len(resp))
+		for _, p := range resp {
+			ids = append(ids, *p.ID)
+			values = append(values, p)
+		}
+		c.cache.putAll(CachePlatformList, ids, values)
 	}
 
 	return resp, nil
 }
 
-// GetEnabledPlatforms gets the avalable platforms, optionally filtered by tag
+// GetEnabledPlatforms gets the enabled private platforms, optionally filtered by tag
 func (c *Client) GetEnabledPlatforms(tag *string) ([]*PlatformConfig, error) {
-	var resp []*PlatformConfig
-	err := c.getJSON(baseURL+platformsConfigPath, &resp)
+	return c.GetEnabledPlatformsWithContext(context.Background(), tag)
+}
 
-	if err != nil {
-		return nil, err
+// GetEnabledPlatformsWithContext is GetEnabledPlatforms with a context for cancellation and deadlines.
+func (c *Client) GetEnabledPlatformsWithContext(ctx context.Context, tag *string) (_ []*PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "GetEnabledPlatforms")
+	defer func() { endSpan(span, err) }()
+
+	enabled := true
+	return c.GetPlatformConfigsWithContext(ctx, &PlatformFilter{Tag: tag, Enabled: &enabled})
+}
+
+// PlatformFilter selects platform configs, unset fields match every platform
+type PlatformFilter struct {
+	// Tag matches platforms with this tag (case-insensitive)
+	Tag *string
+
+	// Category matches platforms of this provider category
+	Category *PlatformCategory
+
+	// Name matches platforms whose name matches this expression
+	Name *regexp.Regexp
+
+	// Enabled matches enabled, or disabled, platforms
+	Enabled *bool
+}
+
+// Matches indicates if a platform config is selected by the filter, a nil filter matches all
+func (f *PlatformFilter) Matches(p *PlatformConfig) bool {
+	if f == nil {
+		return true
 	}
 
-	return resp, nil
+	if f.Tag != nil {
+		found := false
+		if p.Tags != nil {
+			for _, t := range *p.Tags {
+				if strings.EqualFold(t, *f.Tag) {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Category != nil && (p.Category == nil || p.Category.ID == nil || *p.Category.ID != *f.Category) {
+		return false
+	}
+
+	if f.Name != nil && (p.Name == nil || !f.Name.MatchString(*p.Name)) {
+		return false
+	}
+
+	// Platforms are enabled unless stated otherwise
+	if f.Enabled != nil && (p.Enabled == nil || *p.Enabled) != *f.Enabled {
+		return false
+	}
+
+	return true
+}
+
+// GetPlatformConfigs gets the configuration of private platforms selected by a filter (nil for all),
+// in the order Cedexis lists them
+func (c *Client) GetPlatformConfigs(filter *PlatformFilter) ([]*PlatformConfig, error) {
+	return c.GetPlatformConfigsWithContext(context.Background(), filter)
+}
+
+// GetPlatformConfigsWithContext is GetPlatformConfigs with a context for cancellation and deadlines.
+func (c *Client) GetPlatformConfigsWithContext(ctx context.Context, filter *PlatformFilter) (_ []*PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "GetPlatformConfigs")
+	defer func() { endSpan(span, err) }()
+
+	var all []*PlatformConfig
+	if cached, ok := c.cache.list(CachePlatforms); ok {
+		for _, p := range cached {
+			all = append(all, p.(*PlatformConfig))
+		}
+	} else {
+		err = c.getJSON(ctx, c.baseURL+platformsConfigPath, &all)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]int, 0, len(all))
+		values := make([]interface{}, 0, len(all))
+		for _, p := range all {
+			ids = append(ids, *p.ID)
+			values = append(values, p)
+		}
+		c.cache.putAll(CachePlatforms, ids, values)
+	}
+
+	result := make([]*PlatformConfig, 0, len(all))
+	for _, p := range all {
+		if filter.Matches(p) {
+			result = append(result, p)
+		}
+	}
+
+	return result, nil
 }
 
 // CreatePrivatePlatform creates a new platform
 func (c *Client) CreatePrivatePlatform(spec *PlatformConfig) (*PlatformConfig, error) {
+	return c.CreatePrivatePlatformWithContext(context.Background(), spec)
+}
+
+// CreatePrivatePlatformWithContext is CreatePrivatePlatform with a context for cancellation and deadlines.
+func (c *Client) CreatePrivatePlatformWithContext(ctx context.Context, spec *PlatformConfig) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "CreatePrivatePlatform")
+	defer func() { endSpan(span, err) }()
+
+	return c.createPrivatePlatform(ctx, spec, nil)
+}
+
+// createPrivatePlatform creates a platform, validating only the fields that differ from source, the
+// platform it is copied from, if not nil
+func (c *Client) createPrivatePlatform(ctx context.Context, spec *PlatformConfig, source *PlatformConfig) (*PlatformConfig, error) {
+	if err := spec.validateChanges(source); err != nil {
+		return nil, err
+	}
+
 	var resp = &PlatformConfig{}
-	err := c.postJSON(baseURL+platformsConfigPath, spec, resp)
+	err := c.postJSON(ctx, c.baseURL+platformsConfigPath, spec, resp)
 
 	if err != nil {
 		return nil, err
 	}
 
-	c.privatePlatformCache[*resp.ID] = resp
-	c.privatePlatformListCache = map[int]*PlatformInfo{}
+	c.cacheChange(CachePlatforms, *resp.ID, resp)
+	c.cache.invalidateKind(CachePlatformList)
 
 	return resp, nil
 }
 
 // DeletePrivatePlatform removes a platform
 func (c *Client) DeletePrivatePlatform(id int) error {
-	err := c.delete(baseURL + platformsConfigPath + "/" + fmt.Sprintf("%d", id))
+	return c.DeletePrivatePlatformWithContext(context.Background(), id)
+}
 
-	if err != nil {
-		delete(c.privatePlatformCache, id)
-		c.privatePlatformListCache = map[int]*PlatformInfo{}
+// DeletePrivatePlatformWithContext is DeletePrivatePlatform with a context for cancellation and deadlines.
+func (c *Client) DeletePrivatePlatformWithContext(ctx context.Context, id int) (err error) {
+	ctx, span := c.startSpan(ctx, "DeletePrivatePlatform")
+	defer func() { endSpan(span, err) }()
+
+	err = c.delete(ctx, c.baseURL+platformsConfigPath+"/"+fmt.Sprintf("%d", id))
+
+	if err == nil {
+		c.cache.remove(CachePlatforms, id)
+		c.cache.invalidateKind(CachePlatformList)
 	}
 
 	return err
@@ -249,44 +396,184 @@ func (c *Client) DeletePrivatePlatform(id int) error {
 
 // UpdatePrivatePlatform updates a platform
 func (c *Client) UpdatePrivatePlatform(spec *PlatformConfig) error {
+	return c.UpdatePrivatePlatformWithContext(context.Background(), spec)
+}
+
+// UpdatePrivatePlatformWithContext is UpdatePrivatePlatform with a context for cancellation and deadlines.
+func (c *Client) UpdatePrivatePlatformWithContext(ctx context.Context, spec *PlatformConfig) (err error) {
+	ctx, span := c.startSpan(ctx, "UpdatePrivatePlatform")
+	defer func() { endSpan(span, err) }()
+
+	url := c.baseURL + platformsConfigPath + "/" + fmt.Sprintf("%d", *spec.ID)
+
+	// Compare against the live config, spec is often the cached copy edited in place
+	var current *PlatformConfig
+	err = c.getJSON(ctx, url, &current)
+	if err != nil {
+		return err
+	}
+
+	if err = spec.validateChanges(current); err != nil {
+		return err
+	}
+
 	var resp = &PlatformConfig{}
-	err := c.putJSON(baseURL+platformsConfigPath+"/"+fmt.Sprintf("%d", *spec.ID), spec, resp)
+	err = c.putJSON(ctx, url, spec, resp)
 
 	if err == nil {
-		c.privatePlatformCache[*resp.ID] = resp
+		c.cacheChange(CachePlatforms, *resp.ID, resp)
+		c.cache.invalidateKind(CachePlatformList)
+	} else {
+		c.cache.invalidate(CachePlatforms, *spec.ID)
+	}
+
+	return err
+}
+
+// PatchPlatform changes the fields set in patch, sending only those that differ from the
+// platform's current configuration.  Nothing is sent if there are no differences.
+//
+// Experimental: PATCH support has only been tested against cedexistest, not the live API.
+func (c *Client) PatchPlatform(id int, patch *PlatformConfig) (*PlatformConfig, error) {
+	return c.PatchPlatformWithContext(context.Background(), id, patch)
+}
+
+// PatchPlatformWithContext is PatchPlatform with a context for cancellation and deadlines.
+func (c *Client) PatchPlatformWithContext(ctx context.Context, id int, patch *PlatformConfig) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "PatchPlatform")
+	defer func() { endSpan(span, err) }()
+
+	url := c.baseURL + platformsConfigPath + "/" + fmt.Sprintf("%d", id)
+
+	// Compare against the live config, a stale cached copy could hide a change
+	var current *PlatformConfig
+	err = c.getJSON(ctx, url, &current)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = patch.validateChanges(current); err != nil {
+		return nil, err
+	}
+
+	currentObj, err := jsonObject(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patchObj, err := jsonObject(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := jsonChanges(currentObj, patchObj)
+	delete(changes, "id")
+	if len(changes) == 0 {
+		c.cache.put(CachePlatforms, id, current)
+		return current, nil
+	}
+
+	var resp = &PlatformConfig{}
+	err = c.patchJSON(ctx, url, changes, resp)
+	if err != nil {
+		c.cache.invalidate(CachePlatforms, id)
+		return nil, err
+	}
+
+	// A dry run only echoes the changes
+	if c.changeLog != nil {
+		data, err := json.Marshal(jsonMerge(currentObj, changes))
+		if err != nil {
+			return nil, err
+		}
+
+		resp = &PlatformConfig{}
+		if err := json.Unmarshal(data, resp); err != nil {
+			return nil, err
+		}
 	}
 
+	c.cacheChange(CachePlatforms, id, resp)
+	c.cache.invalidateKind(CachePlatformList)
+
+	return resp, nil
+}
+
+// EnablePlatform enables a platform
+func (c *Client) EnablePlatform(id int) error {
+	return c.EnablePlatformWithContext(context.Background(), id)
+}
+
+// EnablePlatformWithContext is EnablePlatform with a context for cancellation and deadlines.
+func (c *Client) EnablePlatformWithContext(ctx context.Context, id int) (err error) {
+	ctx, span := c.startSpan(ctx, "EnablePlatform")
+	defer func() { endSpan(span, err) }()
+
+	return c.setPlatformEnabled(ctx, id, true)
+}
+
+// DisablePlatform disables a platform, without deleting its configuration
+func (c *Client) DisablePlatform(id int) error {
+	return c.DisablePlatformWithContext(context.Background(), id)
+}
+
+// DisablePlatformWithContext is DisablePlatform with a context for cancellation and deadlines.
+func (c *Client) DisablePlatformWithContext(ctx context.Context, id int) (err error) {
+	ctx, span := c.startSpan(ctx, "DisablePlatform")
+	defer func() { endSpan(span, err) }()
+
+	return c.setPlatformEnabled(ctx, id, false)
+}
+
+func (c *Client) setPlatformEnabled(ctx context.Context, id int, enabled bool) error {
+	_, err := c.PatchPlatformWithContext(ctx, id, &PlatformConfig{Enabled: &enabled})
 	return err
 }
 
 // GetPrivatePlatform gets a platform by ID
 func (c *Client) GetPrivatePlatform(id int) (*PlatformConfig, error) {
-	var cfg *PlatformConfig
+	return c.GetPrivatePlatformWithContext(context.Background(), id)
+}
 
-	cfg = c.privatePlatformCache[id]
-	if cfg != nil {
-		return cfg, nil
+// GetPrivatePlatformWithContext is GetPrivatePlatform with a context for cancellation and deadlines.
+func (c *Client) GetPrivatePlatformWithContext(ctx context.Context, id int) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "GetPrivatePlatform")
+	defer func() { endSpan(span, err) }()
+
+	if cached, ok := c.cache.get(CachePlatforms, id); ok {
+		return cached.(*PlatformConfig), nil
 	}
 
-	err := c.getJSON(baseURL+platformsConfigPath+"/"+fmt.Sprintf("%d", id), &cfg)
+	var cfg *PlatformConfig
+	err = c.getJSON(ctx, c.baseURL+platformsConfigPath+"/"+fmt.Sprintf("%d", id), &cfg)
 
 	if err != nil {
-		c.privatePlatformCache[*cfg.ID] = cfg
+		return nil, err
 	}
 
-	return cfg, err
+	c.cache.put(CachePlatforms, id, cfg)
+
+	return cfg, nil
 }
 
 // GetPrivatePlatformByName gets a platform by Name
 func (c *Client) GetPrivatePlatformByName(name string) (*PlatformConfig, error) {
-	platforms, err := c.GetPlatforms(PlatformsTypePrivate)
+	return c.GetPrivatePlatformByNameWithContext(context.Background(), name)
+}
+
+// GetPrivatePlatformByNameWithContext is GetPrivatePlatformByName with a context for cancellation and deadlines.
+func (c *Client) GetPrivatePlatformByNameWithContext(ctx context.Context, name string) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "GetPrivatePlatformByName")
+	defer func() { endSpan(span, err) }()
+
+	platforms, err := c.GetPlatformsWithContext(ctx, PlatformsTypePrivate)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, p := range platforms {
 		if strings.ToLower(name) == strings.ToLower(*p.Name) {
-			return c.GetPrivatePlatform(*p.ID)
+			return c.GetPrivatePlatformWithContext(ctx, *p.ID)
 		}
 	}
 
@@ -384,6 +671,22 @@ func NewPrivatePlatform(
 	}
 }
 
+// validateChanges checks only the Sonar and Fusion fields that differ from current, so settings
+// Cedexis already holds can be written back unchanged
+func (c *PlatformConfig) validateChanges(current *PlatformConfig) error {
+	var sonar *SonarConfig
+	var fusion *FusionCustomConfig
+	if current != nil {
+		sonar = current.SonarConfig
+		fusion = current.FusionCustomConfig
+	}
+
+	if err := c.SonarConfig.changes(sonar).Validate(); err != nil {
+		return err
+	}
+
+	return c.FusionCustomConfig.changes(fusion).Validate()
+}
 
 // DiffersFrom indicates if any fields in this config (that are non-nil) differ from another
 // config.
@@ -0,0 +1,312 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// MinXLProbeSize is the smallest object, in bytes, that gives a meaningful throughput measurement
+	MinXLProbeSize = 100 * 1024
+
+	// MaxXLProbeSize is the largest object, in bytes, Radar will download as an XL probe
+	MaxXLProbeSize = 1024 * 1024
+)
+
+// ProbeKind is the kind of measurement a Radar probe makes
+type ProbeKind int
+
+const (
+	// ProbeKindPrime primes DNS and the connection, measuring availability
+	ProbeKindPrime ProbeKind = iota
+
+	// ProbeKindRTT measures round-trip time, with a small object
+	ProbeKindRTT
+
+	// ProbeKindXL measures throughput, with a large object
+	ProbeKindXL
+
+	// ProbeKindCustom is a custom probe
+	ProbeKindCustom
+)
+
+func (k ProbeKind) String() string {
+	switch k {
+	case ProbeKindPrime:
+		return "prime"
+	case ProbeKindRTT:
+		return "rtt"
+	case ProbeKindXL:
+		return "xl"
+	case ProbeKindCustom:
+		return "custom"
+	default:
+		return fmt.Sprintf("<unknown %d>", int(k))
+	}
+}
+
+// ParseProbeKind parses a probe kind 'prime', 'rtt', 'xl' or 'custom' to enum value
+func ParseProbeKind(val string) (ProbeKind, error) {
+	switch strings.ToLower(val) {
+	case "prime":
+		return ProbeKindPrime, nil
+	case "rtt":
+		return ProbeKindRTT, nil
+	case "xl":
+		return ProbeKindXL, nil
+	case "custom":
+		return ProbeKindCustom, nil
+	default:
+		return 0, fmt.Errorf("Invalid probe kind '%s'", val)
+	}
+}
+
+// ProbeType is a kind of probe over HTTP or, if Secure, HTTPS
+type ProbeType struct {
+	Kind   ProbeKind
+	Secure bool
+}
+
+func (t ProbeType) String() string {
+	if t.Secure {
+		return t.Kind.String() + " (https)"
+	}
+	return t.Kind.String() + " (http)"
+}
+
+// probeTypeIDs maps probe types to the IDs Cedexis reports in PlatformInfo.RadarConfig.ProbeTypes
+var probeTypeIDs = map[ProbeType]int{
+	{ProbeKindPrime, false}:  1,
+	{ProbeKindRTT, false}:    0,
+	{ProbeKindXL, false}:     14,
+	{ProbeKindCustom, false}: 30,
+	{ProbeKindPrime, true}:   23,
+	{ProbeKindRTT, true}:     21,
+	{ProbeKindXL, true}:      22,
+	{ProbeKindCustom, true}:  31,
+}
+
+// ID is the Cedexis ID of the probe type, returning false if the type is unknown
+func (t ProbeType) ID() (int, bool) {
+	id, ok := probeTypeIDs[t]
+	return id, ok
+}
+
+// ProbeTypeForID looks up a probe type by Cedexis ID, returning false if the ID is unknown
+func ProbeTypeForID(id int) (ProbeType, bool) {
+	for t, tid := range probeTypeIDs {
+		if tid == id {
+			return t, true
+		}
+	}
+	return ProbeType{}, false
+}
+
+// ProbeTypes reads the platform's Radar probe type IDs as probe types, ignoring unknown IDs
+func (p *PlatformInfo) ProbeTypes() []ProbeType {
+	if p.RadarConfig == nil {
+		return nil
+	}
+
+	var result []ProbeType
+	for _, pt := range p.RadarConfig.ProbeTypes {
+		if t, ok := ProbeTypeForID(pt.ID); ok {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Probe is a Radar probe of a private platform
+type Probe struct {
+	ProbeType
+
+	// URL of the object measured, which must be https:// for secure probes
+	URL string
+
+	// ObjectType is the type of object, as Cedexis describes it (optional)
+	ObjectType string
+
+	// Size is the size of the object in bytes.  It is required for XL probes, and isn't sent to Cedexis.
+	Size int
+}
+
+// Validate checks the probe URL matches its scheme, and the object size of XL probes
+func (p *Probe) Validate() error {
+	if err := validateURL(p.URL); err != nil {
+		return fmt.Errorf("Invalid %s probe URL: %v", p.ProbeType, err)
+	}
+
+	secure := strings.HasPrefix(strings.ToLower(p.URL), "https://")
+	if secure != p.Secure {
+		return fmt.Errorf("URL '%s' does not match %s probe", p.URL, p.ProbeType)
+	}
+
+	if p.Kind == ProbeKindXL && (p.Size < MinXLProbeSize || p.Size > MaxXLProbeSize) {
+		return fmt.Errorf("XL probe object size %d must be between %d and %d bytes", p.Size, MinXLProbeSize, MaxXLProbeSize)
+	}
+
+	return nil
+}
+
+// probeFields gives the URL and object type fields of a probe type.  Cedexis only has a separate
+// object type for secure prime probes, others share the type of the plain probe.
+func (c *RadarConfig) probeFields(t ProbeType) (url **string, objectType **string) {
+	switch t {
+	case ProbeType{ProbeKindPrime, false}:
+		return &c.PrimeURL, &c.PrimeType
+	case ProbeType{ProbeKindPrime, true}:
+		return &c.PrimeSecureURL, &c.PrimeSecureType
+	case ProbeType{ProbeKindRTT, false}:
+		return &c.RTTURL, &c.RTTType
+	case ProbeType{ProbeKindRTT, true}:
+		return &c.RTTSecureURL, &c.RTTType
+	case ProbeType{ProbeKindXL, false}:
+		return &c.XLURL, &c.XLType
+	case ProbeType{ProbeKindXL, true}:
+		return &c.XLSecureURL, &c.XLType
+	case ProbeType{ProbeKindCustom, false}:
+		return &c.CustomURL, &c.CustomType
+	case ProbeType{ProbeKindCustom, true}:
+		return &c.CustomSecureURL, &c.CustomType
+	default:
+		return nil, nil
+	}
+}
+
+// Probes lists the probes configured, ordered by probe type ID.  Object sizes aren't known.
+func (c *RadarConfig) Probes() []Probe {
+	var result []Probe
+	for t := range probeTypeIDs {
+		url, objectType := c.probeFields(t)
+		if *url == nil {
+			continue
+		}
+
+		p := Probe{ProbeType: t, URL: **url}
+		if *objectType != nil {
+			p.ObjectType = **objectType
+		}
+		result = append(result, p)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return probeTypeIDs[result[i].ProbeType] < probeTypeIDs[result[j].ProbeType] })
+	return result
+}
+
+// AttachProbe validates and sets a probe, replacing any probe of the same type, and enables
+// measurement over the probe's protocol
+func (c *RadarConfig) AttachProbe(p Probe) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	url, objectType := c.probeFields(p.ProbeType)
+	if url == nil {
+		return fmt.Errorf("Invalid probe type %s", p.ProbeType)
+	}
+
+	u := p.URL
+	*url = &u
+	if p.ObjectType != "" {
+		ot := p.ObjectType
+		*objectType = &ot
+	}
+
+	enabled := true
+	if p.Secure {
+		c.HTTPSEnabled = &enabled
+	} else {
+		c.HTTPEnabled = &enabled
+	}
+
+	return nil
+}
+
+// DetachProbe removes the probe of a type, if set, and disables measurement over the probe's
+// protocol if it was the last probe using it
+func (c *RadarConfig) DetachProbe(t ProbeType) {
+	url, objectType := c.probeFields(t)
+	if url == nil {
+		return
+	}
+	*url = nil
+
+	// The object type may be shared with the probe of the same kind over the other protocol
+	otherURL, otherType := c.probeFields(ProbeType{t.Kind, !t.Secure})
+	if otherType != objectType || *otherURL == nil {
+		*objectType = nil
+	}
+
+	for _, p := range c.Probes() {
+		if p.Secure == t.Secure {
+			return
+		}
+	}
+
+	disabled := false
+	if t.Secure {
+		c.HTTPSEnabled = &disabled
+	} else {
+		c.HTTPEnabled = &disabled
+	}
+}
+
+// AttachProbe adds a Radar probe to a private platform, replacing any probe of the same type
+func (c *Client) AttachProbe(platformID int, p Probe) error {
+	return c.AttachProbeWithContext(context.Background(), platformID, p)
+}
+
+// AttachProbeWithContext is AttachProbe with a context for cancellation and deadlines.
+func (c *Client) AttachProbeWithContext(ctx context.Context, platformID int, p Probe) (err error) {
+	ctx, span := c.startSpan(ctx, "AttachProbe")
+	defer func() { endSpan(span, err) }()
+
+	if err = p.Validate(); err != nil {
+		return err
+	}
+
+	return c.updateRadarConfig(ctx, platformID, func(radar *RadarConfig) error {
+		return radar.AttachProbe(p)
+	})
+}
+
+// DetachProbe removes a Radar probe from a private platform
+func (c *Client) DetachProbe(platformID int, t ProbeType) error {
+	return c.DetachProbeWithContext(context.Background(), platformID, t)
+}
+
+// DetachProbeWithContext is DetachProbe with a context for cancellation and deadlines.
+func (c *Client) DetachProbeWithContext(ctx context.Context, platformID int, t ProbeType) (err error) {
+	ctx, span := c.startSpan(ctx, "DetachProbe")
+	defer func() { endSpan(span, err) }()
+
+	return c.updateRadarConfig(ctx, platformID, func(radar *RadarConfig) error {
+		radar.DetachProbe(t)
+		return nil
+	})
+}
+
+// updateRadarConfig applies fn to a copy of the platform's Radar config, and updates the platform.
+// The cached config is shared, so isn't modified in place.
+func (c *Client) updateRadarConfig(ctx context.Context, platformID int, fn func(*RadarConfig) error) error {
+	current, err := c.GetPrivatePlatformWithContext(ctx, platformID)
+	if err != nil {
+		return err
+	}
+
+	spec := *current
+	radar := RadarConfig{}
+	if current.RadarConfig != nil {
+		radar = *current.RadarConfig
+	}
+	spec.RadarConfig = &radar
+
+	if err := fn(&radar); err != nil {
+		return err
+	}
+
+	return c.UpdatePrivatePlatformWithContext(ctx, &spec)
+}
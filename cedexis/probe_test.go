@@ -0,0 +1,102 @@
+package cedexis_test
+
+import (
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestProbes(t *testing.T) {
+	_, c := newTestClient(t)
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	rtt := cedexis.Probe{
+		ProbeType: cedexis.ProbeType{Kind: cedexis.ProbeKindRTT, Secure: true},
+		URL:       "https://cdn.example.com/r20.gif",
+	}
+	xl := cedexis.Probe{
+		ProbeType:  cedexis.ProbeType{Kind: cedexis.ProbeKindXL, Secure: true},
+		URL:        "https://cdn.example.com/r20-100KB.png",
+		ObjectType: "image",
+		Size:       100 * 1024,
+	}
+	for _, probe := range []cedexis.Probe{rtt, xl} {
+		if err := c.AttachProbe(*p.ID, probe); err != nil {
+			t.Fatalf("AttachProbe %s failed: %v", probe.ProbeType, err)
+		}
+	}
+
+	got, err := c.GetPrivatePlatform(*p.ID)
+	if err != nil {
+		t.Fatalf("GetPrivatePlatform failed: %v", err)
+	}
+	probes := got.RadarConfig.Probes()
+	if len(probes) != 2 || probes[0].URL != rtt.URL || probes[1].URL != xl.URL || !*got.RadarConfig.HTTPSEnabled {
+		t.Errorf("Got probes %+v, want rtt and xl over https", probes)
+	}
+
+	if err := c.DetachProbe(*p.ID, rtt.ProbeType); err != nil {
+		t.Fatalf("DetachProbe failed: %v", err)
+	}
+	got, _ = c.GetPrivatePlatform(*p.ID)
+	if probes := got.RadarConfig.Probes(); len(probes) != 1 || probes[0].Kind != cedexis.ProbeKindXL {
+		t.Errorf("Got probes %+v after detach, want xl", probes)
+	}
+
+	// Detaching the last secure probe disables measurement over HTTPS
+	if err := c.DetachProbe(*p.ID, xl.ProbeType); err != nil {
+		t.Fatalf("DetachProbe failed: %v", err)
+	}
+	got, _ = c.GetPrivatePlatform(*p.ID)
+	if probes := got.RadarConfig.Probes(); len(probes) != 0 {
+		t.Errorf("Got probes %+v after detaching all, want none", probes)
+	}
+	if got.RadarConfig.HTTPSEnabled != nil && *got.RadarConfig.HTTPSEnabled {
+		t.Errorf("HTTPS still enabled after detaching the last secure probe")
+	}
+	if got.RadarConfig.XLType != nil {
+		t.Errorf("XL object type %q kept after detaching the last xl probe", *got.RadarConfig.XLType)
+	}
+
+	invalid := map[string]cedexis.Probe{
+		"scheme mismatch": {ProbeType: cedexis.ProbeType{Kind: cedexis.ProbeKindRTT}, URL: "https://cdn.example.com/r20.gif"},
+		"small xl":        {ProbeType: cedexis.ProbeType{Kind: cedexis.ProbeKindXL}, URL: "http://cdn.example.com/r20.gif", Size: 43},
+		"relative URL":    {ProbeType: cedexis.ProbeType{Kind: cedexis.ProbeKindPrime}, URL: "/r20.gif"},
+	}
+	for name, probe := range invalid {
+		if err := c.AttachProbe(*p.ID, probe); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestProbeTypeIDs(t *testing.T) {
+	for _, kind := range []cedexis.ProbeKind{cedexis.ProbeKindPrime, cedexis.ProbeKindRTT, cedexis.ProbeKindXL, cedexis.ProbeKindCustom} {
+		if parsed, err := cedexis.ParseProbeKind(kind.String()); err != nil || parsed != kind {
+			t.Errorf("ParseProbeKind(%q) got (%v, %v)", kind, parsed, err)
+		}
+
+		for _, secure := range []bool{false, true} {
+			pt := cedexis.ProbeType{Kind: kind, Secure: secure}
+			id, ok := pt.ID()
+			if !ok {
+				t.Errorf("%v has no ID", pt)
+			}
+			if got, ok := cedexis.ProbeTypeForID(id); !ok || got != pt {
+				t.Errorf("ProbeTypeForID(%d) got (%v, %v), want %v", id, got, ok, pt)
+			}
+		}
+	}
+
+	if id, ok := (cedexis.ProbeType{Kind: cedexis.ProbeKind(42)}).ID(); ok {
+		t.Errorf("Unknown probe type got ID %d", id)
+	}
+
+	if _, ok := cedexis.ProbeTypeForID(999); ok {
+		t.Errorf("ProbeTypeForID(999) found a probe type")
+	}
+}
@@ -0,0 +1,143 @@
+package cedexis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RadarBuilder builds a RadarConfig, validating it on Build:
+//
+//	radar, err := cedexis.NewRadar().
+//		HTTPS(true).
+//		RTT("https://cdn.example.com/r20.gif").
+//		XL("https://cdn.example.com/r20-100KB.png").
+//		Build()
+//
+// Probe URLs starting https:// set the secure variant of the probe.
+type RadarBuilder struct {
+	cfg RadarConfig
+}
+
+// NewRadar starts building a Radar config, with cache busting, measuring major networks only and
+// without community (public) data
+func NewRadar() *RadarBuilder {
+	cacheBusting := true
+	majorNetworksOnly := true
+	usePublicData := false
+
+	return &RadarBuilder{cfg: RadarConfig{
+		CacheBursting:     &cacheBusting,
+		MajorNetworksOnly: &majorNetworksOnly,
+		UsePublicData:     &usePublicData,
+	}}
+}
+
+// HTTP enables or disables measurement over HTTP
+func (b *RadarBuilder) HTTP(enabled bool) *RadarBuilder {
+	b.cfg.HTTPEnabled = &enabled
+	return b
+}
+
+// HTTPS enables or disables measurement over HTTPS
+func (b *RadarBuilder) HTTPS(enabled bool) *RadarBuilder {
+	b.cfg.HTTPSEnabled = &enabled
+	return b
+}
+
+// UsePublicData uses community measurements of the platform, rather than private probes
+func (b *RadarBuilder) UsePublicData(use bool) *RadarBuilder {
+	b.cfg.UsePublicData = &use
+	return b
+}
+
+// MajorNetworksOnly limits measurements to major networks
+func (b *RadarBuilder) MajorNetworksOnly(only bool) *RadarBuilder {
+	b.cfg.MajorNetworksOnly = &only
+	return b
+}
+
+// CacheBusting adds a unique query string to probes, so they are not served from cache
+func (b *RadarBuilder) CacheBusting(busting bool) *RadarBuilder {
+	b.cfg.CacheBursting = &busting
+	return b
+}
+
+// Prime sets the URL of the connection-priming probe
+func (b *RadarBuilder) Prime(u string) *RadarBuilder {
+	b.probe(u, &b.cfg.PrimeURL, &b.cfg.PrimeSecureURL)
+	return b
+}
+
+// RTT sets the URL of the round-trip time probe (a small object)
+func (b *RadarBuilder) RTT(u string) *RadarBuilder {
+	b.probe(u, &b.cfg.RTTURL, &b.cfg.RTTSecureURL)
+	return b
+}
+
+// XL sets the URL of the throughput probe (a large object)
+func (b *RadarBuilder) XL(u string) *RadarBuilder {
+	b.probe(u, &b.cfg.XLURL, &b.cfg.XLSecureURL)
+	return b
+}
+
+// Custom sets the URL of a custom probe
+func (b *RadarBuilder) Custom(u string) *RadarBuilder {
+	b.probe(u, &b.cfg.CustomURL, &b.cfg.CustomSecureURL)
+	return b
+}
+
+// Weight sets the percentage of measurements made of this platform
+func (b *RadarBuilder) Weight(percent int) *RadarBuilder {
+	enabled := true
+	b.cfg.Weight = &percent
+	b.cfg.WeightEnabled = &enabled
+	return b
+}
+
+func (b *RadarBuilder) probe(u string, plain **string, secure **string) {
+	if strings.HasPrefix(strings.ToLower(u), "https://") {
+		*secure = &u
+	} else {
+		*plain = &u
+	}
+}
+
+// Build validates the configuration, returning the RadarConfig
+func (b *RadarBuilder) Build() (*RadarConfig, error) {
+	cfg := b.cfg
+
+	plain := []*string{cfg.PrimeURL, cfg.RTTURL, cfg.XLURL, cfg.CustomURL}
+	secure := []*string{cfg.PrimeSecureURL, cfg.RTTSecureURL, cfg.XLSecureURL, cfg.CustomSecureURL}
+	for _, u := range append(plain, secure...) {
+		if u != nil {
+			if err := validateURL(*u); err != nil {
+				return nil, fmt.Errorf("Invalid radar probe URL: %v", err)
+			}
+		}
+	}
+
+	// Private measurement needs something to measure
+	if cfg.UsePublicData == nil || !*cfg.UsePublicData {
+		if cfg.HTTPEnabled != nil && *cfg.HTTPEnabled && !anySet(plain) {
+			return nil, fmt.Errorf("Radar requires an http:// probe URL to measure over HTTP")
+		}
+		if cfg.HTTPSEnabled != nil && *cfg.HTTPSEnabled && !anySet(secure) {
+			return nil, fmt.Errorf("Radar requires an https:// probe URL to measure over HTTPS")
+		}
+	}
+
+	if cfg.Weight != nil && (*cfg.Weight < 0 || *cfg.Weight > 100) {
+		return nil, fmt.Errorf("Radar weight %d must be between 0 and 100", *cfg.Weight)
+	}
+
+	return &cfg, nil
+}
+
+func anySet(values []*string) bool {
+	for _, v := range values {
+		if v != nil {
+			return true
+		}
+	}
+	return false
+}
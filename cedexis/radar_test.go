@@ -0,0 +1,38 @@
+package cedexis
+
+import "testing"
+
+func TestRadarBuilder(t *testing.T) {
+	cfg, err := NewRadar().
+		HTTP(true).
+		HTTPS(true).
+		RTT("http://cdn.example.com/r20.gif").
+		RTT("https://cdn.example.com/r20.gif").
+		XL("https://cdn.example.com/r20-100KB.png").
+		Weight(50).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if *cfg.RTTURL != "http://cdn.example.com/r20.gif" || *cfg.RTTSecureURL != "https://cdn.example.com/r20.gif" ||
+		cfg.XLURL != nil || *cfg.XLSecureURL != "https://cdn.example.com/r20-100KB.png" ||
+		*cfg.Weight != 50 || !*cfg.WeightEnabled || !*cfg.CacheBursting {
+		t.Errorf("Incorrect config: %+v", cfg)
+	}
+
+	if _, err := NewRadar().UsePublicData(true).HTTPS(true).Build(); err != nil {
+		t.Errorf("Public data config without probes failed: %v", err)
+	}
+
+	invalid := map[string]*RadarBuilder{
+		"https without probe": NewRadar().HTTPS(true).RTT("http://cdn.example.com/r20.gif"),
+		"invalid URL":         NewRadar().RTT("cdn.example.com/r20.gif"),
+		"weight > 100":        NewRadar().Weight(150),
+	}
+	for name, b := range invalid {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
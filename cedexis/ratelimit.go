@@ -0,0 +1,138 @@
+package cedexis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token-bucket limiter for Cedexis requests.  It is safe for concurrent use, and
+// may be shared by several clients (e.g. clients for the same account) via WithRateLimiter.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+
+	requests  uint64
+	delayed   uint64
+	totalWait time.Duration
+}
+
+// RateLimiterStats is a snapshot of a RateLimiter's state, for metrics
+type RateLimiterStats struct {
+	// Rate is the sustained requests per second allowed
+	Rate float64
+
+	// Burst is the maximum requests allowed at once
+	Burst int
+
+	// Tokens is the number of requests currently available without waiting (negative when
+	// callers are queued)
+	Tokens float64
+
+	// Requests is the total number of requests admitted
+	Requests uint64
+
+	// Delayed is the number of requests that had to wait
+	Delayed uint64
+
+	// TotalWait is the total time requests spent waiting
+	TotalWait time.Duration
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerSecond sustained, with bursts of up to burst.
+// A rate of zero or less does not limit requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds tokens accrued since the last call, must be called with the lock held
+func (l *RateLimiter) refill(now time.Time) {
+	if l.rate <= 0 {
+		l.tokens = float64(l.burst)
+		l.last = now
+		return
+	}
+
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+}
+
+// Wait blocks until a request may be made, or the context is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	_, err := l.wait(ctx)
+	return err
+}
+
+// wait is Wait, also returning how long the request was delayed
+func (l *RateLimiter) wait(ctx context.Context) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	l.mu.Lock()
+	l.refill(time.Now())
+	l.tokens--
+
+	var wait time.Duration
+	if l.tokens < 0 && l.rate > 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			// Give back the reserved token
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+			return 0, err
+		}
+	}
+
+	l.mu.Lock()
+	l.requests++
+	if wait > 0 {
+		l.delayed++
+		l.totalWait += wait
+	}
+	l.mu.Unlock()
+
+	return wait, nil
+}
+
+// Stats returns a snapshot of the limiter's state
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+
+	return RateLimiterStats{
+		Rate:      l.rate,
+		Burst:     l.burst,
+		Tokens:    l.tokens,
+		Requests:  l.requests,
+		Delayed:   l.delayed,
+		TotalWait: l.totalWait,
+	}
+}
+
+// RateLimiter returns the client's limiter, or nil if requests are not limited
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.limiter
+}
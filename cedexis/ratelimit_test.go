@@ -0,0 +1,48 @@
+package cedexis
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait failed: %v", err)
+		}
+	}
+
+	// Two requests are immediate (burst), the next two wait 20ms each
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("4 requests took %v, want at least 40ms", elapsed)
+	}
+
+	stats := l.Stats()
+	if stats.Requests != 4 || stats.Delayed != 2 || stats.Burst != 2 {
+		t.Errorf("Incorrect stats: %+v", stats)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := NewRateLimiter(0.1, 1)
+
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err == nil {
+		t.Errorf("Wait should fail when context deadline exceeded")
+	}
+
+	if stats := l.Stats(); stats.Tokens < -0.5 || stats.Requests != 1 {
+		t.Errorf("Cancelled wait should return its token: %+v", stats)
+	}
+}
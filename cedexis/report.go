@@ -0,0 +1,189 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const radarReportPath = "/reporting/radar.json"
+
+// RadarMetric is a Radar measurement of a platform
+type RadarMetric int
+
+const (
+	// RadarMetricRTT is round-trip time, in milliseconds
+	RadarMetricRTT RadarMetric = iota
+
+	// RadarMetricAvailability is the percentage of probes that succeeded
+	RadarMetricAvailability
+
+	// RadarMetricThroughput is download speed, in kilobits per second
+	RadarMetricThroughput
+)
+
+func (m RadarMetric) String() string {
+	switch m {
+	case RadarMetricRTT:
+		return "rtt"
+	case RadarMetricAvailability:
+		return "availability"
+	case RadarMetricThroughput:
+		return "throughput"
+	default:
+		return fmt.Sprintf("<unknown %d>", int(m))
+	}
+}
+
+// Unit is the unit of the metric's values
+func (m RadarMetric) Unit() string {
+	switch m {
+	case RadarMetricRTT:
+		return "ms"
+	case RadarMetricAvailability:
+		return "%"
+	case RadarMetricThroughput:
+		return "kbps"
+	default:
+		return ""
+	}
+}
+
+// ParseRadarMetric parses a metric 'rtt', 'availability' or 'throughput' to enum value
+func ParseRadarMetric(val string) (RadarMetric, error) {
+	switch strings.ToLower(val) {
+	case "rtt":
+		return RadarMetricRTT, nil
+	case "availability":
+		return RadarMetricAvailability, nil
+	case "throughput":
+		return RadarMetricThroughput, nil
+	default:
+		return 0, fmt.Errorf("Invalid radar metric '%s'", val)
+	}
+}
+
+// RadarQuery selects Radar measurements of a platform
+type RadarQuery struct {
+	PlatformID int
+	Metric     RadarMetric
+
+	// Start and End bound the time window, End defaults to now
+	Start time.Time
+	End   time.Time
+
+	// Interval is the time covered by each sample, or zero for Cedexis to choose
+	Interval time.Duration
+
+	// Market and Country (an ISO code) limit measurements to where they were made from, if set
+	Market  *Market
+	Country string
+}
+
+// RadarSample is the aggregate of Radar measurements made in an interval
+type RadarSample struct {
+	Time time.Time `json:"timestamp"`
+
+	// Value is the mean (RTT and throughput) or percentage (availability) of the measurements
+	Value float64 `json:"value"`
+
+	// Measurements is the number of measurements made
+	Measurements int `json:"measurements"`
+}
+
+// RadarSeries is a time series of Radar measurements of a platform, oldest first
+type RadarSeries struct {
+	PlatformID int
+	Metric     RadarMetric
+	Market     *Market
+	Country    string
+	Samples    []RadarSample
+}
+
+type radarSeriesResponse struct {
+	PlatformID int           `json:"platformId"`
+	Metric     string        `json:"metric"`
+	Market     *Market       `json:"market,omitempty"`
+	Country    string        `json:"country,omitempty"`
+	Samples    []RadarSample `json:"samples"`
+}
+
+// values are the query parameters selecting the measurements
+func (q *RadarQuery) values() (url.Values, error) {
+	end := q.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if q.Start.IsZero() || !q.Start.Before(end) {
+		return nil, fmt.Errorf("Radar query start %v must be before end %v", q.Start, end)
+	}
+
+	v := url.Values{}
+	v.Set("platformId", strconv.Itoa(q.PlatformID))
+	v.Set("metric", q.Metric.String())
+	v.Set("start", q.Start.UTC().Format(time.RFC3339))
+	v.Set("end", end.UTC().Format(time.RFC3339))
+
+	if q.Interval != 0 {
+		if q.Interval < time.Minute {
+			return nil, fmt.Errorf("Radar query interval %v must be at least 1m", q.Interval)
+		}
+		v.Set("interval", strconv.Itoa(int(q.Interval/time.Second)))
+	}
+
+	if q.Market != nil {
+		m, err := ParseMarket(string(*q.Market))
+		if err != nil {
+			return nil, err
+		}
+		v.Set("market", string(m))
+	}
+
+	if q.Country != "" {
+		if len(q.Country) != 2 {
+			return nil, fmt.Errorf("Invalid country code '%s'", q.Country)
+		}
+		v.Set("country", strings.ToUpper(q.Country))
+	}
+
+	return v, nil
+}
+
+// GetRadarSeries gets a time series of Radar measurements of a platform.  The response schema is
+// unverified against the live API.
+func (c *Client) GetRadarSeries(q *RadarQuery) (*RadarSeries, error) {
+	return c.GetRadarSeriesWithContext(context.Background(), q)
+}
+
+// GetRadarSeriesWithContext is GetRadarSeries with a context for cancellation and deadlines.
+func (c *Client) GetRadarSeriesWithContext(ctx context.Context, q *RadarQuery) (_ *RadarSeries, err error) {
+	ctx, span := c.startSpan(ctx, "GetRadarSeries")
+	defer func() { endSpan(span, err) }()
+
+	v, err := q.values()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp radarSeriesResponse
+	err = c.getJSON(ctx, c.baseURL+radarReportPath+"?"+v.Encode(), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	metric, err := ParseRadarMetric(resp.Metric)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RadarSeries{
+		PlatformID: resp.PlatformID,
+		Metric:     metric,
+		Market:     resp.Market,
+		Country:    resp.Country,
+		Samples:    resp.Samples,
+	}, nil
+}
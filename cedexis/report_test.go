@@ -0,0 +1,62 @@
+package cedexis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestRadarSeries(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := []cedexis.RadarSample{
+		{Time: start, Value: 42, Measurements: 1000},
+		{Time: start.Add(time.Hour), Value: 45, Measurements: 900},
+		{Time: start.Add(2 * time.Hour), Value: 50, Measurements: 800},
+	}
+	eu := cedexis.MarketEurope
+	srv.AddRadarSeries(&cedexis.RadarSeries{PlatformID: 7, Metric: cedexis.RadarMetricRTT, Market: &eu, Samples: samples})
+	srv.AddRadarSeries(&cedexis.RadarSeries{PlatformID: 7, Metric: cedexis.RadarMetricAvailability, Country: "FR",
+		Samples: []cedexis.RadarSample{{Time: start, Value: 99.5, Measurements: 10}}})
+
+	series, err := c.GetRadarSeries(&cedexis.RadarQuery{
+		PlatformID: 7,
+		Metric:     cedexis.RadarMetricRTT,
+		Start:      start,
+		End:        start.Add(2 * time.Hour),
+		Interval:   time.Hour,
+		Market:     &eu,
+	})
+	if err != nil {
+		t.Fatalf("GetRadarSeries failed: %v", err)
+	}
+	if series.Metric != cedexis.RadarMetricRTT || *series.Market != eu || len(series.Samples) != 2 ||
+		!series.Samples[1].Time.Equal(start.Add(time.Hour)) || series.Samples[1].Value != 45 {
+		t.Errorf("Incorrect series: %+v", series)
+	}
+
+	series, err = c.GetRadarSeries(&cedexis.RadarQuery{PlatformID: 7, Metric: cedexis.RadarMetricAvailability,
+		Start: start, Country: "fr"})
+	if err != nil || series.Country != "FR" || len(series.Samples) != 1 || series.Samples[0].Value != 99.5 {
+		t.Errorf("GetRadarSeries by country got (%+v, %v)", series, err)
+	}
+
+	bad := cedexis.Market("XY")
+	invalid := map[string]*cedexis.RadarQuery{
+		"no start":       {PlatformID: 7},
+		"end first":      {PlatformID: 7, Start: start, End: start.Add(-time.Hour)},
+		"short interval": {PlatformID: 7, Start: start, Interval: time.Second},
+		"bad market":     {PlatformID: 7, Start: start, Market: &bad},
+		"bad country":    {PlatformID: 7, Start: start, Country: "France"},
+	}
+	for name, q := range invalid {
+		if _, err := c.GetRadarSeries(q); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if n := countRequests(srv, "GET", "/reporting/radar.json"); n != 2 {
+		t.Errorf("Got %d report requests, want 2", n)
+	}
+}
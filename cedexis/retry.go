@@ -0,0 +1,136 @@
+package cedexis
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed request should be attempted again, and after what delay.
+//
+// Retry is called after every failed attempt, with the number of attempts made so far (starting
+// at 1), the request and either the failure response or the transport error.
+type RetryPolicy interface {
+	Retry(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool)
+}
+
+// BackoffRetryPolicy retries with capped exponential back-off and jitter.
+//
+// Requests using non-idempotent methods (e.g. POST) are only retried when Cedexis rate-limits
+// them (429), since the request was refused without being processed.  Other failures are only
+// retried for idempotent methods, so creates are never duplicated.
+type BackoffRetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, doubling for each subsequent retry
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts, including delays requested by Retry-After
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (0-1) of each delay that is randomized
+	Jitter float64
+
+	// RetryableStatus reports whether a response status is worth retrying
+	RetryableStatus func(status int) bool
+}
+
+// DefaultRetryPolicy returns the retry policy used by clients unless overridden
+func DefaultRetryPolicy() *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxAttempts:     5,
+		BaseDelay:       1 * time.Second,
+		MaxDelay:        30 * time.Second,
+		Jitter:          0.2,
+		RetryableStatus: DefaultRetryableStatus,
+	}
+}
+
+// DefaultRetryableStatus retries rate limiting and transient server failures
+func DefaultRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// Retry implements RetryPolicy
+func (p *BackoffRetryPolicy) Retry(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if req.Context().Err() != nil {
+		return 0, false
+	}
+
+	rateLimited := resp != nil && resp.StatusCode == http.StatusTooManyRequests
+	if !rateLimited && !isIdempotent(req.Method) {
+		return 0, false
+	}
+
+	if resp != nil && (p.RetryableStatus == nil || !p.RetryableStatus(resp.StatusCode)) {
+		return 0, false
+	}
+
+	delay := p.BaseDelay << uint(attempt-1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		spread := float64(delay) * p.Jitter
+		delay = delay - time.Duration(spread) + time.Duration(rand.Float64()*2*spread)
+	}
+
+	if resp != nil {
+		if after, ok := retryAfter(resp); ok {
+			delay = after
+		}
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	return delay, true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter parses the Retry-After header, in either delay-seconds or HTTP-date form
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	val := resp.Header.Get("Retry-After")
+	if val == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(val); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(val); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,58 @@
+package cedexis
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoffRetryPolicy(t *testing.T) {
+	p := &BackoffRetryPolicy{
+		MaxAttempts:     3,
+		BaseDelay:       time.Second,
+		MaxDelay:        10 * time.Second,
+		RetryableStatus: DefaultRetryableStatus,
+	}
+
+	netErr := errors.New("connection reset")
+
+	tests := []struct {
+		method  string
+		attempt int
+		status  int
+		err     error
+		header  string
+		retry   bool
+		delay   time.Duration
+	}{
+		{"GET", 1, 503, nil, "", true, time.Second},
+		{"GET", 2, 503, nil, "", true, 2 * time.Second},
+		{"GET", 3, 503, nil, "", false, 0},
+		{"GET", 1, 404, nil, "", false, 0},
+		{"GET", 1, 0, netErr, "", true, time.Second},
+		{"POST", 1, 503, nil, "", false, 0},
+		{"POST", 1, 0, netErr, "", false, 0},
+		{"POST", 1, 429, nil, "", true, time.Second},
+		{"PUT", 1, 429, nil, "7", true, 7 * time.Second},
+		{"DELETE", 1, 429, nil, "120", true, 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		req, _ := http.NewRequest(test.method, "https://example.com/", nil)
+
+		var resp *http.Response
+		if test.err == nil {
+			resp = &http.Response{StatusCode: test.status, Header: http.Header{}}
+			if test.header != "" {
+				resp.Header.Set("Retry-After", test.header)
+			}
+		}
+
+		delay, retry := p.Retry(test.attempt, req, resp, test.err)
+		if retry != test.retry || delay != test.delay {
+			t.Errorf("%s attempt %d (status %d, err %v): got (%v, %v), want (%v, %v)",
+				test.method, test.attempt, test.status, test.err, delay, retry, test.delay, test.retry)
+		}
+	}
+}
@@ -0,0 +1,355 @@
+package cedexis
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// MinSonarInterval is the shortest time allowed between Sonar checks
+	MinSonarInterval = 10 * time.Second
+
+	// MaxSonarInterval is the longest time allowed between Sonar checks
+	MaxSonarInterval = time.Hour
+
+	// DefaultSonarInterval is the time between Sonar checks used by NewSonar
+	DefaultSonarInterval = time.Minute
+)
+
+// SonarMethod is the HTTP method of a Sonar check
+type SonarMethod int
+
+const (
+	// SonarMethodGet checks with a GET request
+	SonarMethodGet SonarMethod = iota
+
+	// SonarMethodHead checks with a HEAD request
+	SonarMethodHead
+
+	// SonarMethodPost checks with a POST request
+	SonarMethodPost
+)
+
+func (m SonarMethod) String() string {
+	switch m {
+	case SonarMethodGet:
+		return "GET"
+	case SonarMethodHead:
+		return "HEAD"
+	case SonarMethodPost:
+		return "POST"
+	default:
+		return fmt.Sprintf("<unknown %d>", int(m))
+	}
+}
+
+// ParseSonarMethod parses a method 'get', 'head' or 'post' to enum value
+func ParseSonarMethod(val string) (SonarMethod, error) {
+	switch strings.ToLower(val) {
+	case "get":
+		return SonarMethodGet, nil
+	case "head":
+		return SonarMethodHead, nil
+	case "post":
+		return SonarMethodPost, nil
+	default:
+		return 0, fmt.Errorf("Invalid sonar method '%s'", val)
+	}
+}
+
+// SonarMatchType is how a Sonar check's response body is matched
+type SonarMatchType int
+
+const (
+	// MatchContains passes the check if the response body contains the match string
+	MatchContains SonarMatchType = iota
+
+	// MatchDoesNotContain passes the check if the response body does not contain the match string
+	MatchDoesNotContain
+
+	// MatchRegex passes the check if the response body matches the match regular expression
+	MatchRegex
+)
+
+func (t SonarMatchType) String() string {
+	switch t {
+	case MatchContains:
+		return "contains"
+	case MatchDoesNotContain:
+		return "doesNotContain"
+	case MatchRegex:
+		return "regex"
+	default:
+		return fmt.Sprintf("<unknown %d>", int(t))
+	}
+}
+
+// ParseSonarMatchType parses a match type 'contains', 'doesNotContain' or 'regex' to enum value
+func ParseSonarMatchType(val string) (SonarMatchType, error) {
+	switch strings.ToLower(val) {
+	case "contains":
+		return MatchContains, nil
+	case "doesnotcontain", "does-not-contain":
+		return MatchDoesNotContain, nil
+	case "regex":
+		return MatchRegex, nil
+	default:
+		return 0, fmt.Errorf("Invalid sonar match type '%s'", val)
+	}
+}
+
+// SonarContentType is a common Content-Type of a Sonar check request, any other media type may
+// also be used (see NormalizeSonarContentType)
+type SonarContentType int
+
+const (
+	// SonarContentTypeJSON is application/json
+	SonarContentTypeJSON SonarContentType = iota
+
+	// SonarContentTypeForm is application/x-www-form-urlencoded
+	SonarContentTypeForm
+
+	// SonarContentTypeXML is application/xml
+	SonarContentTypeXML
+
+	// SonarContentTypeText is text/plain
+	SonarContentTypeText
+)
+
+func (t SonarContentType) String() string {
+	switch t {
+	case SonarContentTypeJSON:
+		return "application/json"
+	case SonarContentTypeForm:
+		return "application/x-www-form-urlencoded"
+	case SonarContentTypeXML:
+		return "application/xml"
+	case SonarContentTypeText:
+		return "text/plain"
+	default:
+		return fmt.Sprintf("<unknown %d>", int(t))
+	}
+}
+
+// NormalizeSonarContentType checks a content type, e.g. 'json' or 'application/json; charset=utf-8',
+// returning the header value to send.  The short names 'json', 'form', 'xml' and 'text' are
+// expanded, any other well-formed media type is returned as is.  It isn't an enum parser because
+// Sonar accepts any media type, SonarContentType only names the common ones.
+func NormalizeSonarContentType(val string) (string, error) {
+	switch strings.ToLower(val) {
+	case "json":
+		return SonarContentTypeJSON.String(), nil
+	case "form":
+		return SonarContentTypeForm.String(), nil
+	case "xml":
+		return SonarContentTypeXML.String(), nil
+	case "text":
+		return SonarContentTypeText.String(), nil
+	}
+
+	if _, _, err := mime.ParseMediaType(val); err != nil {
+		return "", fmt.Errorf("Invalid sonar content type '%s': %v", val, err)
+	}
+	return val, nil
+}
+
+// Validate checks the method and match type hold known values, and the content type is a media type
+func (s *SonarConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Method != nil {
+		if _, err := ParseSonarMethod(*s.Method); err != nil {
+			return err
+		}
+	}
+
+	if s.ResponseMatchType != nil {
+		if _, err := ParseSonarMatchType(*s.ResponseMatchType); err != nil {
+			return err
+		}
+	}
+
+	if s.RequestContentType != nil {
+		if _, _, err := mime.ParseMediaType(*s.RequestContentType); err != nil {
+			return fmt.Errorf("Invalid sonar content type '%s': %v", *s.RequestContentType, err)
+		}
+	}
+
+	return nil
+}
+
+// changes is a copy of s with only the method, match type and content type that differ from
+// current, or s if current is nil
+func (s *SonarConfig) changes(current *SonarConfig) *SonarConfig {
+	if s == nil || current == nil {
+		return s
+	}
+
+	changed := &SonarConfig{}
+	if s.Method != nil && stringsDiffer(s.Method, current.Method) {
+		changed.Method = s.Method
+	}
+	if s.ResponseMatchType != nil && stringsDiffer(s.ResponseMatchType, current.ResponseMatchType) {
+		changed.ResponseMatchType = s.ResponseMatchType
+	}
+	if s.RequestContentType != nil && stringsDiffer(s.RequestContentType, current.RequestContentType) {
+		changed.RequestContentType = s.RequestContentType
+	}
+	return changed
+}
+
+// SonarBuilder builds a SonarConfig, validating it on Build:
+//
+//	sonar, err := cedexis.NewSonar().
+//		URL("https://www.example.com/health").
+//		Method(cedexis.SonarMethodHead).
+//		Interval(30 * time.Second).
+//		ExpectBody("OK", cedexis.MatchContains).
+//		Market(cedexis.MarketEurope).
+//		Build()
+type SonarBuilder struct {
+	cfg       SonarConfig
+	interval  time.Duration
+	timeout   time.Duration
+	matchType *SonarMatchType
+}
+
+// NewSonar starts building an enabled Sonar config, checking with GET every DefaultSonarInterval
+func NewSonar() *SonarBuilder {
+	enabled := true
+	method := SonarMethodGet.String()
+
+	return &SonarBuilder{
+		cfg:      SonarConfig{Enabled: &enabled, Method: &method},
+		interval: DefaultSonarInterval,
+	}
+}
+
+// Enabled turns checking on or off
+func (b *SonarBuilder) Enabled(enabled bool) *SonarBuilder {
+	b.cfg.Enabled = &enabled
+	return b
+}
+
+// URL sets the URL checked
+func (b *SonarBuilder) URL(u string) *SonarBuilder {
+	b.cfg.URL = &u
+	return b
+}
+
+// Method sets the HTTP method of the check
+func (b *SonarBuilder) Method(m SonarMethod) *SonarBuilder {
+	method := m.String()
+	b.cfg.Method = &method
+	return b
+}
+
+// Interval sets the time between checks, between MinSonarInterval and MaxSonarInterval
+func (b *SonarBuilder) Interval(d time.Duration) *SonarBuilder {
+	b.interval = d
+	return b
+}
+
+// Timeout sets how long a check may take before it fails, it must be less than the interval
+func (b *SonarBuilder) Timeout(d time.Duration) *SonarBuilder {
+	b.timeout = d
+	return b
+}
+
+// Host overrides the Host header of the check, rather than taking it from the URL
+func (b *SonarBuilder) Host(host string) *SonarBuilder {
+	b.cfg.Host = &host
+	return b
+}
+
+// Market sets where checks are made from
+func (b *SonarBuilder) Market(m Market) *SonarBuilder {
+	b.cfg.Market = &m
+	return b
+}
+
+// ContentType sets the Content-Type header of the check, e.g. SonarContentTypeJSON.String()
+func (b *SonarBuilder) ContentType(contentType string) *SonarBuilder {
+	b.cfg.RequestContentType = &contentType
+	return b
+}
+
+// ExpectBody checks the response body against match
+func (b *SonarBuilder) ExpectBody(match string, t SonarMatchType) *SonarBuilder {
+	b.cfg.ResponseBodyMatch = &match
+	b.matchType = &t
+	return b
+}
+
+// IgnoreSSLErrors accepts invalid certificates
+func (b *SonarBuilder) IgnoreSSLErrors(ignore bool) *SonarBuilder {
+	b.cfg.IgnoreSSLErrors = &ignore
+	return b
+}
+
+// MaintenanceMode forces the platform to be reported down to Openmix
+func (b *SonarBuilder) MaintenanceMode(maintenance bool) *SonarBuilder {
+	b.cfg.MaintenanceMode = &maintenance
+	return b
+}
+
+// Build validates the configuration, returning the SonarConfig
+func (b *SonarBuilder) Build() (*SonarConfig, error) {
+	cfg := b.cfg
+
+	if cfg.URL != nil {
+		if err := validateURL(*cfg.URL); err != nil {
+			return nil, fmt.Errorf("Invalid sonar URL: %v", err)
+		}
+	} else if cfg.Enabled != nil && *cfg.Enabled {
+		return nil, fmt.Errorf("Sonar requires URL to be enabled")
+	}
+
+	if b.interval < MinSonarInterval || b.interval > MaxSonarInterval {
+		return nil, fmt.Errorf("Sonar interval %v must be between %v and %v", b.interval, MinSonarInterval, MaxSonarInterval)
+	}
+	interval := int(b.interval / time.Second)
+	cfg.PollIntervalSeconds = &interval
+
+	if b.timeout != 0 {
+		if b.timeout < time.Second || b.timeout >= b.interval {
+			return nil, fmt.Errorf("Sonar timeout %v must be at least 1s and less than the interval %v", b.timeout, b.interval)
+		}
+		timeout := int(b.timeout / time.Second)
+		cfg.Timeout = &timeout
+	}
+
+	if b.matchType != nil {
+		matchType := b.matchType.String()
+		cfg.ResponseMatchType = &matchType
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// validateURL checks u is an absolute http or https URL
+func validateURL(u string) error {
+	parsed, err := url.ParseRequestURI(u)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("'%s' is not an http or https URL", u)
+	}
+
+	if parsed.Host == "" {
+		return fmt.Errorf("'%s' has no host", u)
+	}
+
+	return nil
+}
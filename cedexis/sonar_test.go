@@ -0,0 +1,107 @@
+package cedexis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSonarBuilder(t *testing.T) {
+	cfg, err := NewSonar().
+		URL("https://www.example.com/health").
+		Method(SonarMethodHead).
+		Interval(30*time.Second).
+		Timeout(5*time.Second).
+		ExpectBody("OK", MatchContains).
+		Market(MarketEurope).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !*cfg.Enabled || *cfg.URL != "https://www.example.com/health" || *cfg.Method != "HEAD" ||
+		*cfg.PollIntervalSeconds != 30 || *cfg.Timeout != 5 || *cfg.ResponseBodyMatch != "OK" ||
+		*cfg.ResponseMatchType != "contains" || *cfg.Market != MarketEurope {
+		t.Errorf("Incorrect config: %+v", cfg)
+	}
+
+	if cfg, err := NewSonar().Enabled(false).Build(); err != nil || cfg.URL != nil {
+		t.Errorf("Disabled config without URL got (%+v, %v)", cfg, err)
+	}
+
+	invalid := map[string]*SonarBuilder{
+		"enabled without URL": NewSonar(),
+		"relative URL":        NewSonar().URL("/health"),
+		"ftp URL":             NewSonar().URL("ftp://example.com/health"),
+		"short interval":      NewSonar().URL("http://example.com").Interval(time.Second),
+		"long interval":       NewSonar().URL("http://example.com").Interval(2 * time.Hour),
+		"timeout > interval":  NewSonar().URL("http://example.com").Interval(10 * time.Second).Timeout(20 * time.Second),
+		"unknown match type":  NewSonar().URL("http://example.com").ExpectBody("OK", SonarMatchType(42)),
+		"unknown method":      NewSonar().URL("http://example.com").Method(SonarMethod(42)),
+	}
+	for name, b := range invalid {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSonarEnums(t *testing.T) {
+	for _, m := range []SonarMethod{SonarMethodGet, SonarMethodHead, SonarMethodPost} {
+		if parsed, err := ParseSonarMethod(m.String()); err != nil || parsed != m {
+			t.Errorf("ParseSonarMethod(%q) got (%v, %v)", m, parsed, err)
+		}
+	}
+	for _, mt := range []SonarMatchType{MatchContains, MatchDoesNotContain, MatchRegex} {
+		if parsed, err := ParseSonarMatchType(mt.String()); err != nil || parsed != mt {
+			t.Errorf("ParseSonarMatchType(%q) got (%v, %v)", mt, parsed, err)
+		}
+	}
+	for _, ct := range []SonarContentType{SonarContentTypeJSON, SonarContentTypeForm, SonarContentTypeXML, SonarContentTypeText} {
+		if normalized, err := NormalizeSonarContentType(ct.String()); err != nil || normalized != ct.String() {
+			t.Errorf("NormalizeSonarContentType(%q) got (%v, %v)", ct, normalized, err)
+		}
+	}
+
+	if ct, err := NormalizeSonarContentType("json"); err != nil || ct != "application/json" {
+		t.Errorf("NormalizeSonarContentType(json) got (%v, %v)", ct, err)
+	}
+
+	for _, ct := range []string{"application/json; charset=utf-8", "application/vnd.api+json"} {
+		if normalized, err := NormalizeSonarContentType(ct); err != nil || normalized != ct {
+			t.Errorf("NormalizeSonarContentType(%q) got (%v, %v)", ct, normalized, err)
+		}
+		if err := (&SonarConfig{RequestContentType: &ct}).Validate(); err != nil {
+			t.Errorf("Validating content type %s failed: %v", ct, err)
+		}
+	}
+
+	badContentType := "application/json;;"
+	if err := (&SonarConfig{RequestContentType: &badContentType}).Validate(); err == nil {
+		t.Errorf("Expected error validating content type %s", badContentType)
+	}
+
+	bad := "PATCH"
+	if err := (&SonarConfig{Method: &bad}).Validate(); err == nil {
+		t.Errorf("Expected error validating method %s", bad)
+	}
+}
+
+func TestValidateChanges(t *testing.T) {
+	method := "OPTIONS"
+	current := &PlatformConfig{SonarConfig: &SonarConfig{Method: &method}}
+
+	// Unchanged server state is written back
+	same := "OPTIONS"
+	if err := (&PlatformConfig{SonarConfig: &SonarConfig{Method: &same}}).validateChanges(current); err != nil {
+		t.Errorf("Validating unchanged method failed: %v", err)
+	}
+
+	// A new value is still checked
+	changed := "PATCH"
+	if err := (&PlatformConfig{SonarConfig: &SonarConfig{Method: &changed}}).validateChanges(current); err == nil {
+		t.Errorf("Expected error validating changed method %s", changed)
+	}
+	if err := (&PlatformConfig{SonarConfig: &SonarConfig{Method: &same}}).validateChanges(nil); err == nil {
+		t.Errorf("Expected error validating method %s of a new platform", same)
+	}
+}
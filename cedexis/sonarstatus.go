@@ -0,0 +1,237 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sonarReportPath = "/reporting/sonar.json"
+const sonarHistoryPath = "/reporting/sonar.json/history"
+
+// SonarState is whether Sonar considers a platform up
+type SonarState int
+
+const (
+	// SonarStateUnknown is before the first check, or when Sonar is disabled
+	SonarStateUnknown SonarState = iota
+
+	// SonarStateUp is passing checks
+	SonarStateUp
+
+	// SonarStateDown is failing checks
+	SonarStateDown
+)
+
+func (s SonarState) String() string {
+	switch s {
+	case SonarStateUnknown:
+		return "unknown"
+	case SonarStateUp:
+		return "up"
+	case SonarStateDown:
+		return "down"
+	default:
+		return fmt.Sprintf("<unknown %d>", int(s))
+	}
+}
+
+// ParseSonarState parses a state 'up', 'down' or 'unknown' to enum value
+func ParseSonarState(val string) (SonarState, error) {
+	switch strings.ToLower(val) {
+	case "unknown", "":
+		return SonarStateUnknown, nil
+	case "up":
+		return SonarStateUp, nil
+	case "down":
+		return SonarStateDown, nil
+	default:
+		return 0, fmt.Errorf("Invalid sonar state '%s'", val)
+	}
+}
+
+// SonarMarketStatus is the Sonar state of a platform checked from one market
+type SonarMarketStatus struct {
+	Market      Market
+	State       SonarState
+	Since       time.Time
+	LastChecked time.Time
+}
+
+// SonarStatus is the current Sonar state of a platform, overall and by market
+type SonarStatus struct {
+	PlatformID  int
+	State       SonarState
+	Since       time.Time
+	LastChecked time.Time
+	Markets     []SonarMarketStatus
+}
+
+// SonarTransition is a change of Sonar state
+type SonarTransition struct {
+	Time time.Time
+
+	// Market is where the change was seen from, or nil for the platform's overall state
+	Market *Market
+
+	From SonarState
+	To   SonarState
+
+	// Reason is why checks started failing, e.g. a timeout, if Cedexis gives one
+	Reason string
+}
+
+// SonarHistory is the Sonar state changes of a platform in a time window, oldest first
+type SonarHistory struct {
+	PlatformID  int
+	Start       time.Time
+	End         time.Time
+	Transitions []SonarTransition
+}
+
+// Flaps counts the times the platform's overall state went down
+func (h *SonarHistory) Flaps() int {
+	n := 0
+	for _, t := range h.Transitions {
+		if t.Market == nil && t.To == SonarStateDown {
+			n++
+		}
+	}
+	return n
+}
+
+type sonarMarketStatusResponse struct {
+	Market      Market    `json:"market"`
+	State       string    `json:"state"`
+	Since       time.Time `json:"since"`
+	LastChecked time.Time `json:"lastChecked"`
+}
+
+type sonarStatusResponse struct {
+	PlatformID  int                         `json:"platformId"`
+	State       string                      `json:"state"`
+	Since       time.Time                   `json:"since"`
+	LastChecked time.Time                   `json:"lastChecked"`
+	Markets     []sonarMarketStatusResponse `json:"markets"`
+}
+
+type sonarTransitionResponse struct {
+	Time   time.Time `json:"timestamp"`
+	Market *Market   `json:"market,omitempty"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+func (r *sonarStatusResponse) status() (*SonarStatus, error) {
+	state, err := ParseSonarState(r.State)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &SonarStatus{PlatformID: r.PlatformID, State: state, Since: r.Since, LastChecked: r.LastChecked}
+	for _, m := range r.Markets {
+		state, err := ParseSonarState(m.State)
+		if err != nil {
+			return nil, err
+		}
+		s.Markets = append(s.Markets, SonarMarketStatus{Market: m.Market, State: state, Since: m.Since, LastChecked: m.LastChecked})
+	}
+	return s, nil
+}
+
+// GetSonarStatuses gets the current Sonar state of all private platforms.  The response schema is
+// unverified against the live API.
+func (c *Client) GetSonarStatuses() ([]*SonarStatus, error) {
+	return c.GetSonarStatusesWithContext(context.Background())
+}
+
+// GetSonarStatusesWithContext is GetSonarStatuses with a context for cancellation and deadlines.
+func (c *Client) GetSonarStatusesWithContext(ctx context.Context) (_ []*SonarStatus, err error) {
+	ctx, span := c.startSpan(ctx, "GetSonarStatuses")
+	defer func() { endSpan(span, err) }()
+
+	var resp []*sonarStatusResponse
+	err = c.getJSON(ctx, c.baseURL+sonarReportPath, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*SonarStatus, 0, len(resp))
+	for _, r := range resp {
+		s, err := r.status()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+// GetSonarStatus gets the current Sonar state of a private platform.  The response schema is
+// unverified against the live API.
+func (c *Client) GetSonarStatus(platformID int) (*SonarStatus, error) {
+	return c.GetSonarStatusWithContext(context.Background(), platformID)
+}
+
+// GetSonarStatusWithContext is GetSonarStatus with a context for cancellation and deadlines.
+func (c *Client) GetSonarStatusWithContext(ctx context.Context, platformID int) (_ *SonarStatus, err error) {
+	ctx, span := c.startSpan(ctx, "GetSonarStatus")
+	defer func() { endSpan(span, err) }()
+
+	var resp sonarStatusResponse
+	err = c.getJSON(ctx, c.baseURL+sonarReportPath+"/"+fmt.Sprintf("%d", platformID), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.status()
+}
+
+// GetSonarHistory gets the Sonar state changes of a private platform between start and end.  The
+// response schema is unverified against the live API.
+func (c *Client) GetSonarHistory(platformID int, start time.Time, end time.Time) (*SonarHistory, error) {
+	return c.GetSonarHistoryWithContext(context.Background(), platformID, start, end)
+}
+
+// GetSonarHistoryWithContext is GetSonarHistory with a context for cancellation and deadlines.
+func (c *Client) GetSonarHistoryWithContext(ctx context.Context, platformID int, start time.Time, end time.Time) (_ *SonarHistory, err error) {
+	ctx, span := c.startSpan(ctx, "GetSonarHistory")
+	defer func() { endSpan(span, err) }()
+
+	if !start.Before(end) {
+		return nil, fmt.Errorf("Sonar history start %v must be before end %v", start, end)
+	}
+
+	v := url.Values{}
+	v.Set("platformId", strconv.Itoa(platformID))
+	v.Set("start", start.UTC().Format(time.RFC3339))
+	v.Set("end", end.UTC().Format(time.RFC3339))
+
+	var resp []sonarTransitionResponse
+	err = c.getJSON(ctx, c.baseURL+sonarHistoryPath+"?"+v.Encode(), &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &SonarHistory{PlatformID: platformID, Start: start, End: end}
+	for _, r := range resp {
+		from, err := ParseSonarState(r.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := ParseSonarState(r.To)
+		if err != nil {
+			return nil, err
+		}
+		h.Transitions = append(h.Transitions, SonarTransition{Time: r.Time, Market: r.Market, From: from, To: to, Reason: r.Reason})
+	}
+	sort.SliceStable(h.Transitions, func(i, j int) bool { return h.Transitions[i].Time.Before(h.Transitions[j].Time) })
+
+	return h, nil
+}
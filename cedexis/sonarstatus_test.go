@@ -0,0 +1,58 @@
+package cedexis_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestSonarStatus(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	eu := cedexis.MarketEurope
+	srv.SetSonarStatus(&cedexis.SonarStatus{
+		PlatformID: 7,
+		State:      cedexis.SonarStateUp,
+		Since:      now.Add(-time.Hour),
+		Markets: []cedexis.SonarMarketStatus{
+			{Market: cedexis.MarketEurope, State: cedexis.SonarStateDown, Since: now.Add(-time.Minute)},
+			{Market: cedexis.MarketNorthAmerica, State: cedexis.SonarStateUp, Since: now.Add(-time.Hour)},
+		},
+	})
+	srv.AddSonarTransition(7, cedexis.SonarTransition{Time: now.Add(-time.Minute), Market: &eu, From: cedexis.SonarStateUp, To: cedexis.SonarStateDown, Reason: "timeout"})
+	srv.AddSonarTransition(7, cedexis.SonarTransition{Time: now.Add(-2 * time.Hour), From: cedexis.SonarStateUp, To: cedexis.SonarStateDown})
+	srv.AddSonarTransition(7, cedexis.SonarTransition{Time: now.Add(-time.Hour), From: cedexis.SonarStateDown, To: cedexis.SonarStateUp})
+	srv.AddSonarTransition(7, cedexis.SonarTransition{Time: now.Add(-48 * time.Hour), From: cedexis.SonarStateUp, To: cedexis.SonarStateDown})
+
+	status, err := c.GetSonarStatus(7)
+	if err != nil {
+		t.Fatalf("GetSonarStatus failed: %v", err)
+	}
+	if status.State != cedexis.SonarStateUp || len(status.Markets) != 2 || status.Markets[0].State != cedexis.SonarStateDown ||
+		!status.Since.Equal(now.Add(-time.Hour)) {
+		t.Errorf("Incorrect status: %+v", status)
+	}
+
+	if statuses, err := c.GetSonarStatuses(); err != nil || len(statuses) != 1 {
+		t.Errorf("GetSonarStatuses got (%v, %v)", statuses, err)
+	}
+
+	if _, err := c.GetSonarStatus(8); !cedexis.IsNotFound(err) {
+		t.Errorf("Got error %v, want not found", err)
+	}
+
+	h, err := c.GetSonarHistory(7, now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("GetSonarHistory failed: %v", err)
+	}
+	if len(h.Transitions) != 3 || h.Flaps() != 1 || h.Transitions[0].To != cedexis.SonarStateDown ||
+		*h.Transitions[2].Market != eu || h.Transitions[2].Reason != "timeout" {
+		t.Errorf("Incorrect history: %+v", h)
+	}
+
+	if _, err := c.GetSonarHistory(7, now, now.Add(-time.Hour)); err == nil {
+		t.Errorf("Expected error for reversed window")
+	}
+}
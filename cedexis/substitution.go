@@ -0,0 +1,293 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SubstitutionGraph is which platforms substitute data for which private platforms, when the
+// private platform has too little of its own
+type SubstitutionGraph struct {
+	// Sources are the substitution sources of each private platform, by ID
+	Sources map[int][]int
+
+	// Names are the platform names, by ID
+	Names map[int]string
+}
+
+// SubstitutionCycleError is returned when substitution sources would refer back to a platform
+type SubstitutionCycleError struct {
+	// Cycle is the platform names, starting and ending with the same platform
+	Cycle []string
+}
+
+func (e *SubstitutionCycleError) Error() string {
+	return fmt.Sprintf("Substitution sources form a cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// GetSubstitutionGraph gets the substitution sources of all private platforms
+func (c *Client) GetSubstitutionGraph() (*SubstitutionGraph, error) {
+	return c.GetSubstitutionGraphWithContext(context.Background())
+}
+
+// GetSubstitutionGraphWithContext is GetSubstitutionGraph with a context for cancellation and deadlines.
+func (c *Client) GetSubstitutionGraphWithContext(ctx context.Context) (_ *SubstitutionGraph, err error) {
+	ctx, span := c.startSpan(ctx, "GetSubstitutionGraph")
+	defer func() { endSpan(span, err) }()
+
+	configs, err := c.GetPlatformConfigsWithContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	platforms, err := c.GetPlatformsWithContext(ctx, PlatformsTypeAll)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &SubstitutionGraph{Sources: map[int][]int{}, Names: map[int]string{}}
+	for _, p := range platforms {
+		if p.ID != nil && p.Name != nil {
+			g.Names[*p.ID] = *p.Name
+		}
+	}
+	for _, p := range configs {
+		if p.ID == nil {
+			continue
+		}
+		if p.Name != nil {
+			g.Names[*p.ID] = *p.Name
+		}
+		if p.PlatformSubstitutionSources != nil && len(*p.PlatformSubstitutionSources) > 0 {
+			g.Sources[*p.ID] = append([]int(nil), *p.PlatformSubstitutionSources...)
+		}
+	}
+
+	return g, nil
+}
+
+// With is a copy of the graph with the sources of a platform replaced
+func (g *SubstitutionGraph) With(id int, sources []int) *SubstitutionGraph {
+	cp := &SubstitutionGraph{Sources: make(map[int][]int, len(g.Sources)), Names: g.Names}
+	for k, v := range g.Sources {
+		cp.Sources[k] = v
+	}
+
+	if len(sources) > 0 {
+		cp.Sources[id] = sources
+	} else {
+		delete(cp.Sources, id)
+	}
+	return cp
+}
+
+// Cycle finds a platform that is, through its sources, a substitution source of itself.  It returns
+// the platform IDs around the cycle, starting and ending with the same platform, or nil if there is
+// no cycle.
+func (g *SubstitutionGraph) Cycle() []int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[int]int{}
+
+	var path []int
+	var visit func(id int) []int
+	visit = func(id int) []int {
+		state[id] = visiting
+		path = append(path, id)
+
+		for _, src := range g.Sources[id] {
+			switch state[src] {
+			case visiting:
+				for i, p := range path {
+					if p == src {
+						return append(append([]int(nil), path[i:]...), src)
+					}
+				}
+			case unvisited:
+				if cycle := visit(src); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	for _, id := range g.ids() {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// Validate returns a SubstitutionCycleError if the graph has a cycle
+func (g *SubstitutionGraph) Validate() error {
+	cycle := g.Cycle()
+	if cycle == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(cycle))
+	for _, id := range cycle {
+		names = append(names, g.Name(id))
+	}
+	return &SubstitutionCycleError{Cycle: names}
+}
+
+// Name of a platform in the graph, or its ID if the name isn't known
+func (g *SubstitutionGraph) Name(id int) string {
+	if name, ok := g.Names[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", id)
+}
+
+// String shows each platform with substitution sources, one per line, e.g. "web -> cdn1, cdn2"
+func (g *SubstitutionGraph) String() string {
+	lines := make([]string, 0, len(g.Sources))
+	for id, sources := range g.Sources {
+		names := make([]string, 0, len(sources))
+		for _, src := range sources {
+			names = append(names, g.Name(src))
+		}
+		lines = append(lines, g.Name(id)+" -> "+strings.Join(names, ", "))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// ids are the platforms with sources, in order so cycles are found deterministically
+func (g *SubstitutionGraph) ids() []int {
+	ids := make([]int, 0, len(g.Sources))
+	for id := range g.Sources {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// AddSubstitutionSources adds platforms, by name, as substitution sources of a private platform.  The
+// sources must exist, share the platform's category, and not create a cycle.
+func (c *Client) AddSubstitutionSources(platformID int, names ...string) (*PlatformConfig, error) {
+	return c.AddSubstitutionSourcesWithContext(context.Background(), platformID, names...)
+}
+
+// AddSubstitutionSourcesWithContext is AddSubstitutionSources with a context for cancellation and deadlines.
+func (c *Client) AddSubstitutionSourcesWithContext(ctx context.Context, platformID int, names ...string) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "AddSubstitutionSources")
+	defer func() { endSpan(span, err) }()
+
+	return c.updateSubstitutionSources(ctx, platformID, names, func(current []int, ids []int) []int {
+		for _, id := range ids {
+			if !containsInt(current, id) {
+				current = append(current, id)
+			}
+		}
+		return current
+	})
+}
+
+// RemoveSubstitutionSources removes platforms, by name, from the substitution sources of a private
+// platform
+func (c *Client) RemoveSubstitutionSources(platformID int, names ...string) (*PlatformConfig, error) {
+	return c.RemoveSubstitutionSourcesWithContext(context.Background(), platformID, names...)
+}
+
+// RemoveSubstitutionSourcesWithContext is RemoveSubstitutionSources with a context for cancellation and deadlines.
+func (c *Client) RemoveSubstitutionSourcesWithContext(ctx context.Context, platformID int, names ...string) (_ *PlatformConfig, err error) {
+	ctx, span := c.startSpan(ctx, "RemoveSubstitutionSources")
+	defer func() { endSpan(span, err) }()
+
+	return c.updateSubstitutionSources(ctx, platformID, names, func(current []int, ids []int) []int {
+		result := []int{}
+		for _, id := range current {
+			if !containsInt(ids, id) {
+				result = append(result, id)
+			}
+		}
+		return result
+	})
+}
+
+// updateSubstitutionSources resolves names, applies change to the platform's sources, checks the
+// result and patches the platform
+func (c *Client) updateSubstitutionSources(ctx context.Context, platformID int, names []string,
+	change func(current []int, ids []int) []int) (*PlatformConfig, error) {
+
+	current, err := c.GetPrivatePlatformWithContext(ctx, platformID)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := c.GetSubstitutionGraphWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	platforms, err := c.GetPlatformsWithContext(ctx, PlatformsTypeAll)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(names))
+	for _, name := range names {
+		src, err := substitutionSource(current, platforms, name)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, *src.ID)
+	}
+
+	sources := []int{}
+	if current.PlatformSubstitutionSources != nil {
+		sources = append(sources, *current.PlatformSubstitutionSources...)
+	}
+	sources = change(sources, ids)
+
+	if err := g.With(platformID, sources).Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.PatchPlatformWithContext(ctx, platformID, &PlatformConfig{PlatformSubstitutionSources: &sources})
+}
+
+// substitutionSource finds a platform by name, checking it can be a source for p
+func substitutionSource(p *PlatformConfig, platforms []*PlatformInfo, name string) (*PlatformInfo, error) {
+	for _, src := range platforms {
+		if src.Name == nil || *src.Name != name || src.ID == nil {
+			continue
+		}
+
+		if p.ID != nil && *src.ID == *p.ID {
+			return nil, fmt.Errorf("Platform '%s' cannot substitute for itself", name)
+		}
+
+		if p.Category == nil || p.Category.ID == nil || src.Category == nil || src.Category.ID == nil ||
+			*p.Category.ID != *src.Category.ID {
+			return nil, fmt.Errorf("Platform '%s' is not in the same category as the platform it substitutes for", name)
+		}
+
+		return src, nil
+	}
+
+	return nil, fmt.Errorf("Platform '%s' not found", name)
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
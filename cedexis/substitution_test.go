@@ -0,0 +1,67 @@
+package cedexis_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestSubstitutionSources(t *testing.T) {
+	srv, c := newTestClient(t)
+
+	create := func(name string) int {
+		p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform(name, name, "", nil))
+		if err != nil {
+			t.Fatalf("CreatePrivatePlatform failed: %v", err)
+		}
+		return *p.ID
+	}
+	a, b, third := create("a"), create("b"), create("c")
+
+	cloud := cedexis.PlatformCategoryCloudComputing
+	name := "aws"
+	srv.AddCommunityPlatform(&cedexis.PlatformInfo{Name: &name, Category: &cedexis.NameID{ID: &cloud}})
+
+	p, err := c.AddSubstitutionSources(a, "b", "c")
+	if err != nil {
+		t.Fatalf("AddSubstitutionSources failed: %v", err)
+	}
+	if !reflect.DeepEqual(*p.PlatformSubstitutionSources, []int{b, third}) {
+		t.Errorf("Got sources %v, want [%d %d]", *p.PlatformSubstitutionSources, b, third)
+	}
+
+	if _, err := c.AddSubstitutionSources(b, "c"); err != nil {
+		t.Fatalf("AddSubstitutionSources failed: %v", err)
+	}
+
+	var cycle *cedexis.SubstitutionCycleError
+	if _, err := c.AddSubstitutionSources(third, "a"); !errors.As(err, &cycle) ||
+		!reflect.DeepEqual(cycle.Cycle, []string{"a", "b", "c", "a"}) && !reflect.DeepEqual(cycle.Cycle, []string{"a", "c", "a"}) {
+		t.Errorf("Got error %v, want cycle through a", err)
+	}
+
+	invalid := map[string]string{
+		"missing":        "nope",
+		"self":           "c",
+		"other category": "aws",
+	}
+	for desc, source := range invalid {
+		if _, err := c.AddSubstitutionSources(third, source); err == nil {
+			t.Errorf("%s: expected error", desc)
+		}
+	}
+
+	g, err := c.GetSubstitutionGraph()
+	if err != nil {
+		t.Fatalf("GetSubstitutionGraph failed: %v", err)
+	}
+	if s := g.String(); s != "a -> b, c\nb -> c" {
+		t.Errorf("Got graph %q", s)
+	}
+
+	if p, err := c.RemoveSubstitutionSources(a, "b"); err != nil || !reflect.DeepEqual(*p.PlatformSubstitutionSources, []int{third}) {
+		t.Errorf("RemoveSubstitutionSources got (%v, %v), want [%d]", p, err, third)
+	}
+}
@@ -0,0 +1,76 @@
+package cedexis
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"time"
+)
+
+// Logger receives diagnostic output from the client, it is satisfied by *log.Logger
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// TraceMode controls how much of each HTTP exchange is logged
+type TraceMode int
+
+const (
+	// TraceOff disables wire tracing
+	TraceOff TraceMode = iota
+
+	// TraceRequests logs method, URL, status and latency of each request
+	TraceRequests
+
+	// TraceBodies logs as TraceRequests, plus headers and bodies with credentials redacted
+	TraceBodies
+)
+
+var redactPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?im)^(Authorization:[ \t]*)[^\r\n]+`),
+	regexp.MustCompile(`((?:client_secret|access_token|refresh_token|id_token)=)[^&\s]*`),
+	regexp.MustCompile(`("(?:client_secret|access_token|refresh_token|id_token)"\s*:\s*")[^"]*`),
+}
+
+// redact masks OAuth tokens and client secrets in a dumped request or response
+func redact(dump []byte) []byte {
+	for _, re := range redactPatterns {
+		dump = re.ReplaceAll(dump, []byte("${1}[REDACTED]"))
+	}
+	return dump
+}
+
+// tracingTransport logs HTTP exchanges, it sits beneath the OAuth transport so sees token
+// requests as well as API calls
+type tracingTransport struct {
+	base   http.RoundTripper
+	logger Logger
+	mode   TraceMode
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode >= TraceBodies {
+		if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+			t.logger.Printf("--> %s", redact(dump))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		t.logger.Printf("%s %s failed after %v: %v", req.Method, req.URL, latency, err)
+		return nil, err
+	}
+
+	t.logger.Printf("%s %s %d (%v)", req.Method, req.URL, resp.StatusCode, latency)
+
+	if t.mode >= TraceBodies {
+		if dump, err := httputil.DumpResponse(resp, true); err == nil {
+			t.logger.Printf("<-- %s", redact(dump))
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,217 @@
+package cedexis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// MeasurementWeighting is the share of Radar measurements made of a platform, overall and in
+// particular markets and countries.  Percentages are 0..100, a nil Weight or empty list is unweighted.
+type MeasurementWeighting struct {
+	Weight *int
+
+	Markets      []Market
+	MarketWeight int
+
+	// Countries are ISO codes, e.g. 'FR'
+	Countries     []string
+	CountryWeight int
+}
+
+// FormatMarketList formats markets in the RadarConfig.MarketWeightList format
+func FormatMarketList(markets []Market) string {
+	codes := make([]string, 0, len(markets))
+	for _, m := range markets {
+		codes = append(codes, string(m))
+	}
+	return strings.Join(codes, ",")
+}
+
+// ParseMarketList parses a RadarConfig.MarketWeightList
+func ParseMarketList(list string) ([]Market, error) {
+	var result []Market
+	for _, code := range splitList(list) {
+		m, err := ParseMarket(code)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// FormatCountryList formats country ISO codes in the RadarConfig.IsoWeightList format
+func FormatCountryList(codes []string) string {
+	upper := make([]string, 0, len(codes))
+	for _, code := range codes {
+		upper = append(upper, strings.ToUpper(code))
+	}
+	return strings.Join(upper, ",")
+}
+
+// ParseCountryList parses a RadarConfig.IsoWeightList to country ISO codes
+func ParseCountryList(list string) []string {
+	codes := splitList(list)
+	for i, code := range codes {
+		codes[i] = strings.ToUpper(code)
+	}
+	return codes
+}
+
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Validate checks the percentages and markets, and if countries is not nil, that the country codes
+// are amongst them (see GetCountries)
+func (w *MeasurementWeighting) Validate(countries []*Country) error {
+	for _, percent := range []*int{w.Weight, &w.MarketWeight, &w.CountryWeight} {
+		if percent != nil && (*percent < 0 || *percent > 100) {
+			return fmt.Errorf("Weight %d must be between 0 and 100", *percent)
+		}
+	}
+
+	for _, m := range w.Markets {
+		if _, err := ParseMarket(string(m)); err != nil {
+			return err
+		}
+	}
+
+	known := make(map[string]bool, len(countries))
+	for _, c := range countries {
+		known[strings.ToUpper(c.ISOCode)] = true
+	}
+	for _, code := range w.Countries {
+		if len(code) != 2 {
+			return fmt.Errorf("Invalid country code '%s'", code)
+		}
+		if countries != nil && !known[strings.ToUpper(code)] {
+			return fmt.Errorf("Unknown country code '%s'", code)
+		}
+	}
+
+	return nil
+}
+
+// MeasurementWeighting reads the platform's Radar weighting
+func (p *PlatformConfig) MeasurementWeighting() (*MeasurementWeighting, error) {
+	w := &MeasurementWeighting{}
+	radar := p.RadarConfig
+	if radar == nil {
+		return w, nil
+	}
+
+	if radar.WeightEnabled != nil && *radar.WeightEnabled && radar.Weight != nil {
+		weight := *radar.Weight
+		w.Weight = &weight
+	}
+
+	if radar.MarketWeightEnabled != nil && *radar.MarketWeightEnabled && radar.MarketWeightList != nil {
+		markets, err := ParseMarketList(*radar.MarketWeightList)
+		if err != nil {
+			return nil, err
+		}
+		w.Markets = markets
+		if radar.MarketWeight != nil {
+			w.MarketWeight = *radar.MarketWeight
+		}
+	}
+
+	if radar.IsoWeightEnabled != nil && *radar.IsoWeightEnabled && radar.IsoWeightList != nil {
+		w.Countries = ParseCountryList(*radar.IsoWeightList)
+		if radar.IsoWeight != nil {
+			w.CountryWeight = *radar.IsoWeight
+		}
+	}
+
+	return w, nil
+}
+
+// SetMeasurementWeighting validates and sets the platform's Radar weighting, enabling each kind of
+// weighting only when it is given.  Country codes aren't checked against GetCountries, see
+// Client.SetMeasurementWeighting.
+func (p *PlatformConfig) SetMeasurementWeighting(w *MeasurementWeighting) error {
+	if err := w.Validate(nil); err != nil {
+		return err
+	}
+
+	if p.RadarConfig == nil {
+		p.RadarConfig = &RadarConfig{}
+	}
+	radar := p.RadarConfig
+
+	weightEnabled := w.Weight != nil
+	radar.WeightEnabled = &weightEnabled
+	radar.Weight = nil
+	if weightEnabled {
+		weight := *w.Weight
+		radar.Weight = &weight
+	}
+
+	marketEnabled := len(w.Markets) > 0
+	radar.MarketWeightEnabled = &marketEnabled
+	radar.MarketWeight, radar.MarketWeightList = nil, nil
+	if marketEnabled {
+		weight := w.MarketWeight
+		list := FormatMarketList(w.Markets)
+		radar.MarketWeight, radar.MarketWeightList = &weight, &list
+	}
+
+	isoEnabled := len(w.Countries) > 0
+	radar.IsoWeightEnabled = &isoEnabled
+	radar.IsoWeight, radar.IsoWeightList = nil, nil
+	if isoEnabled {
+		weight := w.CountryWeight
+		list := FormatCountryList(w.Countries)
+		radar.IsoWeight, radar.IsoWeightList = &weight, &list
+	}
+
+	return nil
+}
+
+// SetMeasurementWeighting sets the Radar weighting of a private platform, checking country codes
+// against GetCountries
+func (c *Client) SetMeasurementWeighting(platformID int, w *MeasurementWeighting) error {
+	return c.SetMeasurementWeightingWithContext(context.Background(), platformID, w)
+}
+
+// SetMeasurementWeightingWithContext is SetMeasurementWeighting with a context for cancellation and deadlines.
+func (c *Client) SetMeasurementWeightingWithContext(ctx context.Context, platformID int, w *MeasurementWeighting) (err error) {
+	ctx, span := c.startSpan(ctx, "SetMeasurementWeighting")
+	defer func() { endSpan(span, err) }()
+
+	if len(w.Countries) > 0 {
+		countries, err := c.GetCountriesWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		if err := w.Validate(countries); err != nil {
+			return err
+		}
+	}
+
+	current, err := c.GetPrivatePlatformWithContext(ctx, platformID)
+	if err != nil {
+		return err
+	}
+
+	// The cached config is shared, so isn't modified in place
+	spec := *current
+	if current.RadarConfig != nil {
+		radar := *current.RadarConfig
+		spec.RadarConfig = &radar
+	}
+
+	if err := spec.SetMeasurementWeighting(w); err != nil {
+		return err
+	}
+
+	return c.UpdatePrivatePlatformWithContext(ctx, &spec)
+}
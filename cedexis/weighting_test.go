@@ -0,0 +1,77 @@
+package cedexis_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestMeasurementWeighting(t *testing.T) {
+	srv, c := newTestClient(t)
+	srv.AddCountry(&cedexis.Country{Location: cedexis.Location{ID: 1, ISOCode: "FR", Name: "France"}})
+	srv.AddCountry(&cedexis.Country{Location: cedexis.Location{ID: 2, ISOCode: "DE", Name: "Germany"}})
+
+	p, err := c.CreatePrivatePlatform(cedexis.NewPrivatePlatform("web", "Web", "", nil))
+	if err != nil {
+		t.Fatalf("CreatePrivatePlatform failed: %v", err)
+	}
+
+	weight := 50
+	w := &cedexis.MeasurementWeighting{
+		Weight:        &weight,
+		Markets:       []cedexis.Market{cedexis.MarketEurope, cedexis.MarketAsia},
+		MarketWeight:  80,
+		Countries:     []string{"fr", "DE"},
+		CountryWeight: 100,
+	}
+	if err := c.SetMeasurementWeighting(*p.ID, w); err != nil {
+		t.Fatalf("SetMeasurementWeighting failed: %v", err)
+	}
+
+	got, _ := c.GetPrivatePlatform(*p.ID)
+	radar := got.RadarConfig
+	if *radar.MarketWeightList != "EU,AS" || *radar.IsoWeightList != "FR,DE" ||
+		!*radar.WeightEnabled || !*radar.MarketWeightEnabled || !*radar.IsoWeightEnabled {
+		t.Errorf("Incorrect radar config: %+v", radar)
+	}
+
+	read, err := got.MeasurementWeighting()
+	if err != nil {
+		t.Fatalf("MeasurementWeighting failed: %v", err)
+	}
+	w.Countries = []string{"FR", "DE"}
+	if !reflect.DeepEqual(read, w) {
+		t.Errorf("Got weighting %+v, want %+v", read, w)
+	}
+
+	// Clearing weighting disables it
+	if err := c.SetMeasurementWeighting(*p.ID, &cedexis.MeasurementWeighting{}); err != nil {
+		t.Fatalf("SetMeasurementWeighting failed: %v", err)
+	}
+	got, _ = c.GetPrivatePlatform(*p.ID)
+	if radar := got.RadarConfig; *radar.WeightEnabled || *radar.MarketWeightEnabled || *radar.IsoWeightEnabled || radar.IsoWeightList != nil {
+		t.Errorf("Incorrect radar config after clearing: %+v", radar)
+	}
+
+	invalid := map[string]*cedexis.MeasurementWeighting{
+		"unknown country": {Countries: []string{"ZZ"}, CountryWeight: 10},
+		"unknown market":  {Markets: []cedexis.Market{"XY"}},
+		"weight > 100":    {MarketWeight: 101, Markets: []cedexis.Market{cedexis.MarketEurope}},
+	}
+	for name, w := range invalid {
+		if err := c.SetMeasurementWeighting(*p.ID, w); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseMarketList(t *testing.T) {
+	markets, err := cedexis.ParseMarketList(" eu, NA ,")
+	if err != nil || !reflect.DeepEqual(markets, []cedexis.Market{cedexis.MarketEurope, cedexis.MarketNorthAmerica}) {
+		t.Errorf("ParseMarketList got (%v, %v)", markets, err)
+	}
+	if _, err := cedexis.ParseMarketList("EU,Mars"); err == nil {
+		t.Errorf("Expected error parsing unknown market")
+	}
+}
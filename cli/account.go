@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+// envAccount is the account using credentials from the environment, it is kept apart from any
+// 'default' profile
+const envAccount = "env"
+
+// accounts holds a client for each account used so far
+var accounts = cedexis.NewMultiClient()
+
+// accountCredentials are the credentials of each known account.  They are only resolved, which
+// may run a credential helper, when the account is first used.
+var accountCredentials = map[string]cedexis.CredentialsProvider{}
+
+var accountOptions []cedexis.ClientOption
+
+var activeAccount string
+
+// addAccount registers the credentials of an account, an account already using the name is kept
+func addAccount(name string, creds cedexis.CredentialsProvider) {
+	if _, ok := accountCredentials[name]; ok {
+		fmt.Printf("Warning: account '%s' is already defined, ignoring the profile of the same name\n", name)
+		return
+	}
+	accountCredentials[name] = creds
+}
+
+// loadAccounts adds an account for each profile in the credentials file, if there is one.  Clients
+// are created with opts.
+func loadAccounts(opts ...cedexis.ClientOption) {
+	accountOptions = opts
+
+	path := cedexis.DefaultCredentialsFile()
+	profiles, err := cedexis.LoadProfiles(path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		addAccount(name, &cedexis.ProfileCredentials{Path: path, Profile: name})
+	}
+}
+
+// accountNames lists the known accounts, in order
+func accountNames() []string {
+	names := make([]string, 0, len(accountCredentials))
+	for name := range accountCredentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// accountClient gets the client of an account, creating it on first use
+func accountClient(ctx context.Context, name string) (*cedexis.Client, error) {
+	if c := accounts.Client(name); c != nil {
+		return c, nil
+	}
+
+	creds, ok := accountCredentials[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown account '%s'", name)
+	}
+
+	c, err := cedexis.NewClientWithCredentials(ctx, creds, accountOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("Account '%s': %v", name, err)
+	}
+
+	accounts.Add(name, c)
+	return c, nil
+}
+
+// useAccount makes an account the target of all commands, discarding anything cached from the
+// previous account
+func useAccount(name string) error {
+	c, err := accountClient(context.Background(), name)
+	if err != nil {
+		return err
+	}
+
+	cClient = c
+	activeAccount = name
+
+	alerts = nil
+	apps = nil
+	zones = nil
+	platforms = map[cedexis.PlatformType][]*cedexis.PlatformInfo{}
+	fusionArchetypes = nil
+	archetypes = nil
+
+	return nil
+}
@@ -51,6 +51,30 @@ const (
 
 	// CmdFragZone represents the "xxx zone" sub-command
 	CmdFragZone
+
+	// CmdFragUse represents the "use" command
+	CmdFragUse
+
+	// CmdFragAccount represents the "xxx account" sub-command
+	CmdFragAccount
+
+	// CmdFragClone represents the "xxx xxx clone" sub-command
+	CmdFragClone
+
+	// CmdFragFusion represents the "xxx xxx fusion" sub-command
+	CmdFragFusion
+
+	// CmdFragSubstitute represents the "platform substitute xxx" sub-command
+	CmdFragSubstitute
+
+	// CmdFragAdd represents the "xxx xxx add" sub-command
+	CmdFragAdd
+
+	// CmdFragRemove represents the "xxx xxx remove" sub-command
+	CmdFragRemove
+
+	// CmdFragStatus represents the "status" command
+	CmdFragStatus
 )
 
 const (
@@ -60,6 +84,12 @@ const (
 	// CmdCreateCloudPlatform represents command "create platform cloud"
 	CmdCreateCloudPlatform CommandCode = CommandCode(int(CmdFragCreate | (CmdFragPlatform << 8) | (CmdFragCloud << 16)))
 
+	// CmdCreatePlatformClone represents command "create platform clone"
+	CmdCreatePlatformClone CommandCode = CommandCode(int(CmdFragCreate | (CmdFragPlatform << 8) | (CmdFragClone << 16)))
+
+	// CmdCreateFusionPlatform represents command "create platform fusion"
+	CmdCreateFusionPlatform CommandCode = CommandCode(int(CmdFragCreate | (CmdFragPlatform << 8) | (CmdFragFusion << 16)))
+
 	// CmdDeletePlatform represents command "delete platform <name>"
 	CmdDeletePlatform CommandCode = CommandCode(int(CmdFragDelete | (CmdFragPlatform << 8)))
 
@@ -108,6 +138,21 @@ const (
 	// CmdDeleteZone represents commdn "delete zone"
 	CmdDeleteZone CommandCode = CommandCode(int(CmdFragDelete | (CmdFragZone << 8)))
 
+	// CmdUseAccount represents command "use account"
+	CmdUseAccount CommandCode = CommandCode(int(CmdFragUse | (CmdFragAccount << 8)))
+
+	// CmdAddSubstitute represents command "platform substitute add"
+	CmdAddSubstitute CommandCode = CommandCode(int(CmdFragPlatform | (CmdFragSubstitute << 8) | (CmdFragAdd << 16)))
+
+	// CmdRemoveSubstitute represents command "platform substitute remove"
+	CmdRemoveSubstitute CommandCode = CommandCode(int(CmdFragPlatform | (CmdFragSubstitute << 8) | (CmdFragRemove << 16)))
+
+	// CmdShowSubstitutes represents command "platform substitute show"
+	CmdShowSubstitutes CommandCode = CommandCode(int(CmdFragPlatform | (CmdFragSubstitute << 8) | (CmdFragShow << 16)))
+
+	// CmdStatusPlatform represents command "status platform"
+	CmdStatusPlatform CommandCode = CommandCode(int(CmdFragStatus | (CmdFragPlatform << 8)))
+
 	// CmdExit represents "exit" command
 	CmdExit CommandCode = CommandCode(int(CmdFragExit))
 )
@@ -115,6 +160,8 @@ const (
 var commandCodeNames = map[CommandCode]string{
 	CmdNone:                   "CmdNone",
 	CmdCreateCloudPlatform:    "CmdCreateCloudPlatform",
+	CmdCreatePlatformClone:    "CmdCreatePlatformClone",
+	CmdCreateFusionPlatform:   "CmdCreateFusionPlatform",
 	CmdDeletePlatform:         "CmdDeletePlatform",
 	CmdListCommunityPlatforms: "CmdListPublicPlatforms",
 	CmdListPrivatePlatforms:   "CmdListPrivatePlatforms",
@@ -131,6 +178,11 @@ var commandCodeNames = map[CommandCode]string{
 	CmdShowZone:               "CmdShowZone",
 	CmdCreateZone:             "CmdCreateZone",
 	CmdDeleteZone:             "CmdDeleteZone",
+	CmdUseAccount:             "CmdUseAccount",
+	CmdAddSubstitute:          "CmdAddSubstitute",
+	CmdRemoveSubstitute:       "CmdRemoveSubstitute",
+	CmdShowSubstitutes:        "CmdShowSubstitutes",
+	CmdStatusPlatform:         "CmdStatusPlatform",
 	CmdExit:                   "CmdExit",
 }
 
@@ -168,6 +220,13 @@ const (
 	argAvailabilityThreshold   string = "availabilityThreshold"
 	argTargetCname             string = "targetCname"
 	argZoneFile                string = "zoneFile"
+	argSource                  string = "source"
+	argAccount                 string = "account"
+	argArchetype               string = "archetype"
+	argLoadURL                 string = "loadURL"
+	argLoadRate                string = "loadRate"
+	argView                    string = "view"
+	argHours                   string = "hours"
 )
 
 var commandSpec = map[string]parser.CommandFrag{
@@ -183,20 +242,44 @@ var commandSpec = map[string]parser.CommandFrag{
 					PosArgs: []parser.PosArg{{Name: argName, Desc: "Name of platform"}},
 					Args: map[string]parser.NamedArg{
 						argShortName:               {Desc: "Set the shortname"},
-						argRegion:                  {Desc: "Set the public cloud region", Suggest: suggestCloudPlatforms},
+						argRegion:                  {Desc: "Set the public cloud region, e.g. \"aws eu-west-1\"", Suggest: suggestCloudPlatforms},
 						argTags:                    {Desc: "Set tags on the new platform"},
 						argSonarEnabled:            {Desc: "Enable sonar health-checks"},
 						argSonarURL:                {Desc: "URL to check"},
 						argSonarPollInterval:       {Desc: "Seconds between checks"},
 						argSonarTimeout:            {Desc: "Timeout for health-check"},
-						argSonarMethod:             {Desc: "HTTP method for health-check"},
+						argSonarMethod:             {Desc: "HTTP method for health-check", Suggest: suggestSonarMethod},
 						argSonarIgnoreSSLErrors:    {Desc: "Accept invalid SSL certs"},
 						argSonarMaintenanceMode:    {Desc: "Force state down to Openmix"},
 						argSonarHost:               {Desc: "Override host from URL"},
 						argSonarMarket:             {Desc: "Source for health-checks", Suggest: suggestSonarMarket},
-						argSonarRequestContentType: {Desc: "Request Content-Type header"},
+						argSonarRequestContentType: {Desc: "Request Content-Type header", Suggest: suggestSonarContentType},
 						argSonarResponseBodyMatch:  {Desc: "Any string"},
-						argSonarResponseMatchType:  {Desc: "Pass vs fail based on body match"},
+						argSonarResponseMatchType:  {Desc: "Pass vs fail based on body match", Suggest: suggestSonarMatchType},
+					}},
+				"fusion": {Desc: "New private platform fed by Fusion",
+					Code:    int(CmdCreateFusionPlatform),
+					Handler: handleCreateFusionPlatform,
+					PosArgs: []parser.PosArg{{Name: argName, Desc: "Name of platform"}},
+					Args: map[string]parser.NamedArg{
+						argDescription: {Desc: "Set the description"},
+						argShortName:   {Desc: "Set the shortname"},
+						argArchetype:   {Desc: "Fusion data feed", Suggest: suggestFusionArchetypes},
+						argLoadURL:     {Desc: "URL to load data from"},
+						argLoadRate:    {Desc: "Seconds between loads"},
+						argTags:        {Desc: "Set tags on the new platform"},
+					}},
+				"clone": {Desc: "New private platform copying another",
+					Code:    int(CmdCreatePlatformClone),
+					Handler: handleClonePlatform,
+					PosArgs: []parser.PosArg{
+						{Name: argSource, Desc: "Name of platform to copy", Suggest: suggestPrivatePlatforms},
+						{Name: argName, Desc: "Name of new platform"},
+					},
+					Args: map[string]parser.NamedArg{
+						argDescription: {Desc: "Change the description"},
+						argAccount:     {Desc: "Create in another account", Suggest: suggestAccounts},
+						argRegion:      {Desc: "Change the public cloud region, e.g. \"aws eu-west-1\"", Suggest: suggestCloudPlatforms},
 					}},
 			}},
 			"alert": {Desc: "Create a new alert",
@@ -296,5 +379,50 @@ var commandSpec = map[string]parser.CommandFrag{
 			},
 		},
 	},
+	"use": {Desc: "Switch account",
+		Sub: map[string]parser.CommandFrag{
+			"account": {Desc: "Switch the active account",
+				Code:    int(CmdUseAccount),
+				Handler: handleUseAccount,
+				PosArgs: []parser.PosArg{{Name: argName, Desc: "Name of account", Suggest: suggestAccounts}},
+			},
+		},
+	},
+	"platform": {Desc: "Manage platforms",
+		Sub: map[string]parser.CommandFrag{
+			"substitute": {Desc: "Manage substitution sources", Sub: map[string]parser.CommandFrag{
+				"add": {Desc: "Add a substitution source",
+					Code:    int(CmdAddSubstitute),
+					Handler: handleSubstitute,
+					PosArgs: []parser.PosArg{
+						{Name: argName, Desc: "Name of platform", Suggest: suggestPrivatePlatforms},
+						{Name: argSource, Desc: "Name of source platform", Suggest: suggestAllPlatforms},
+					}},
+				"remove": {Desc: "Remove a substitution source",
+					Code:    int(CmdRemoveSubstitute),
+					Handler: handleSubstitute,
+					PosArgs: []parser.PosArg{
+						{Name: argName, Desc: "Name of platform", Suggest: suggestPrivatePlatforms},
+						{Name: argSource, Desc: "Name of source platform", Suggest: suggestAllPlatforms},
+					}},
+				"show": {Desc: "Show substitution sources of all platforms",
+					Code:    int(CmdShowSubstitutes),
+					Handler: handleSubstitute},
+			}},
+		},
+	},
+	"status": {Desc: "Show health",
+		Sub: map[string]parser.CommandFrag{
+			"platform": {Desc: "Show Sonar status of a platform",
+				Code:    int(CmdStatusPlatform),
+				Handler: handleStatus,
+				PosArgs: []parser.PosArg{{Name: argName, Desc: "Name of platform", Suggest: suggestPrivatePlatforms}},
+				Args: map[string]parser.NamedArg{
+					argView:  {Desc: "Show as table or timeline", Suggest: suggestStatusViews},
+					argHours: {Desc: "Hours of history (default 24)"},
+				},
+			},
+		},
+	},
 	"exit": {Desc: "Exit", Code: int(CmdExit)},
 }
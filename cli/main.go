@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
-	"net/url"
+	"log"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/ctxkenb/cedexis-golang/cedexis"
@@ -40,7 +42,15 @@ func executor(cmd string) {
 	}
 
 	if command.Handler != nil {
+		if changeLog != nil {
+			changeLog.Reset()
+		}
+
 		command.Handler(command)
+
+		if changeLog != nil {
+			printChanges(changeLog.Changes())
+		}
 		return
 	}
 
@@ -54,7 +64,7 @@ func handleCreatePlatform(command *parser.Command) {
 	}
 
 	cat := cedexis.PlatformCategoryCloudComputing
-	platformID, err := getPlatformID(command.Args[argRegion], cedexis.PlatformsTypeCommunity, &cat)
+	platformID, err := getArchetypeID(command.Args[argRegion], &cat)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -68,12 +78,75 @@ func handleCreatePlatform(command *parser.Command) {
 		return
 	}
 
-	if sonar.Enabled != nil && *sonar.Enabled == true && sonar.URL == nil {
-		fmt.Println("Error: Sonar requires URL to be enabled")
+	err = createPlatform(command.Args[argName], shortName, command.Args[argDescription], platformID, tags, sonar)
+	if err != nil {
+		fmt.Println(err)
 		return
 	}
+}
 
-	err = createPlatform(command.Args[argName], shortName, command.Args[argDescription], platformID, tags, sonar)
+func handleCreateFusionPlatform(command *parser.Command) {
+	shortName := command.Args[argShortName]
+	if shortName == "" {
+		shortName = strings.Replace(command.Args[argName], " ", "_", -1)
+	}
+
+	if command.Args[argArchetype] == "" {
+		fmt.Println("Fusion platforms need an archetype")
+		return
+	}
+
+	loadRate := 5 * time.Minute
+	loadRateSeconds, err := parseInt(command.Args[argLoadRate])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if loadRateSeconds != nil {
+		loadRate = time.Duration(*loadRateSeconds) * time.Second
+	}
+
+	tags := strings.Split(command.Args[argTags], ",")
+
+	err = createFusionPlatform(command.Args[argName], shortName, command.Args[argDescription],
+		command.Args[argArchetype], command.Args[argLoadURL], loadRate, tags)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+}
+
+func handleClonePlatform(command *parser.Command) {
+	target := cClient
+	if command.Args[argAccount] != "" {
+		var err error
+		target, err = accountClient(context.Background(), command.Args[argAccount])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	overrides := &cedexis.PlatformConfig{}
+	name := command.Args[argName]
+	overrides.Name = &name
+	overrides.DisplayName = &name
+	if command.Args[argDescription] != "" {
+		description := command.Args[argDescription]
+		overrides.IntendedUse = &description
+	}
+
+	if command.Args[argRegion] != "" {
+		cat := cedexis.PlatformCategoryCloudComputing
+		archetypeID, err := getArchetypeID(command.Args[argRegion], &cat)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		overrides.PublicProviderArchetypeID = &archetypeID
+	}
+
+	err := clonePlatform(command.Args[argSource], target, overrides)
 	if err != nil {
 		fmt.Println(err)
 		return
@@ -439,70 +512,185 @@ func handleDeleteZone(command *parser.Command) {
 	}
 }
 
+func handleSubstitute(command *parser.Command) {
+	var err error
+	switch CommandCode(command.Code) {
+	case CmdAddSubstitute:
+		err = substitutePlatform(command.Args[argName], command.Args[argSource], true)
+	case CmdRemoveSubstitute:
+		err = substitutePlatform(command.Args[argName], command.Args[argSource], false)
+	case CmdShowSubstitutes:
+		err = showSubstitutes()
+	}
+
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func handleStatus(command *parser.Command) {
+	view := command.Args[argView]
+	if view == "" {
+		view = viewTable
+	}
+	if view != viewTable && view != viewTimeline {
+		fmt.Printf("Unknown view '%s'\n", view)
+		return
+	}
+
+	hours, err := parseInt(command.Args[argHours])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	window := 24 * time.Hour
+	if hours != nil {
+		window = time.Duration(*hours) * time.Hour
+	}
+
+	platformID, err := getPlatformID(command.Args[argName], cedexis.PlatformsTypePrivate, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	status, err := cClient.GetSonarStatus(platformID)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	now := time.Now()
+	history, err := cClient.GetSonarHistory(platformID, now.Add(-window), now)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	w, _, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w == 0 {
+		w = 80
+	}
+
+	if view == viewTimeline {
+		fmt.Print(sonarTimeline(status, history, w))
+	} else {
+		sonarStatusToTable(status).Print(os.Stdout, w)
+	}
+	fmt.Printf("Went down %d times in the last %v\n", history.Flaps(), window)
+}
+
+func handleUseAccount(command *parser.Command) {
+	err := useAccount(command.Args[argName])
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	err = cClient.Ping()
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
 func parseSonarConfig(vars map[string]string) (*cedexis.SonarConfig, error) {
+	sonar := cedexis.NewSonar()
+
 	sonarEnabled, err := parseBool(vars[argSonarEnabled])
 	if err != nil {
 		return nil, err
 	}
 
-	sonarURL, err := parseURL(vars[argSonarURL])
-	if err != nil {
-		return nil, err
+	// Without a URL there is nothing to check
+	if sonarEnabled != nil {
+		sonar.Enabled(*sonarEnabled)
+	} else if vars[argSonarURL] == "" {
+		sonar.Enabled(false)
+	}
+
+	if vars[argSonarURL] != "" {
+		sonar.URL(vars[argSonarURL])
 	}
 
 	sonarPollInterval, err := parseInt(vars[argSonarPollInterval])
 	if err != nil {
 		return nil, err
 	}
+	if sonarPollInterval != nil {
+		sonar.Interval(time.Duration(*sonarPollInterval) * time.Second)
+	}
 
-	sonarTimeout, err := parseInt(vars[argSonarPollInterval])
+	sonarTimeout, err := parseInt(vars[argSonarTimeout])
 	if err != nil {
 		return nil, err
 	}
+	if sonarTimeout != nil {
+		sonar.Timeout(time.Duration(*sonarTimeout) * time.Second)
+	}
 
 	sonarIgnoreSSLErrors, err := parseBool(vars[argSonarIgnoreSSLErrors])
 	if err != nil {
 		return nil, err
 	}
+	if sonarIgnoreSSLErrors != nil {
+		sonar.IgnoreSSLErrors(*sonarIgnoreSSLErrors)
+	}
 
 	sonarMaintenanceMode, err := parseBool(vars[argSonarMaintenanceMode])
 	if err != nil {
 		return nil, err
 	}
+	if sonarMaintenanceMode != nil {
+		sonar.MaintenanceMode(*sonarMaintenanceMode)
+	}
+
+	if vars[argSonarMethod] != "" {
+		method, err := cedexis.ParseSonarMethod(vars[argSonarMethod])
+		if err != nil {
+			return nil, err
+		}
+		sonar.Method(method)
+	}
+	if vars[argSonarHost] != "" {
+		sonar.Host(vars[argSonarHost])
+	}
+	if vars[argSonarMarket] != "" {
+		sonar.Market(cedexis.Market(vars[argSonarMarket]))
+	}
+	if vars[argSonarRequestContentType] != "" {
+		contentType, err := cedexis.NormalizeSonarContentType(vars[argSonarRequestContentType])
+		if err != nil {
+			return nil, err
+		}
+		sonar.ContentType(contentType)
+	}
+
+	if vars[argSonarResponseBodyMatch] != "" {
+		matchType := cedexis.MatchContains
+		if vars[argSonarResponseMatchType] != "" {
+			matchType, err = cedexis.ParseSonarMatchType(vars[argSonarResponseMatchType])
+			if err != nil {
+				return nil, err
+			}
+		}
+		sonar.ExpectBody(vars[argSonarResponseBodyMatch], matchType)
+	}
 
-	sonarMethod := stringOrNil(vars[argSonarMethod])
-	if sonarMethod == nil {
-		val := "GET"
-		sonarMethod = &val
-	}
-
-	sonarHost := stringOrNil(vars[argSonarHost])
-	sonarMarket := (*cedexis.Market)(stringOrNil(vars[argSonarMarket]))
-	sonarRequestContentType := stringOrNil(vars[argSonarRequestContentType])
-	sonarResponseBodyMatch := stringOrNil(vars[argSonarResponseBodyMatch])
-	sonarResponseMatchType := stringOrNil(vars[argSonarResponseMatchType])
-
-	return &cedexis.SonarConfig{
-		Enabled:             sonarEnabled,
-		URL:                 sonarURL,
-		PollIntervalSeconds: sonarPollInterval,
-		Timeout:             sonarTimeout,
-		Method:              sonarMethod,
-		IgnoreSSLErrors:     sonarIgnoreSSLErrors,
-		MaintenanceMode:     sonarMaintenanceMode,
-		Host:                sonarHost,
-		Market:              sonarMarket,
-		RequestContentType:  sonarRequestContentType,
-		ResponseBodyMatch:   sonarResponseBodyMatch,
-		ResponseMatchType:   sonarResponseMatchType,
-	}, nil
+	return sonar.Build()
 }
 
-func stringOrNil(s string) *string {
-	if s == "" {
-		return nil
+func printChanges(changes []cedexis.Change) {
+	for _, c := range changes {
+		fmt.Printf("[dry-run] %s %s", c.Method, c.URL)
+		if c.ID != nil {
+			fmt.Printf(" (id %d)", *c.ID)
+		}
+		fmt.Println()
+
+		if c.Body != "" {
+			fmt.Println("  " + c.Body)
+		}
 	}
-	return &s
 }
 
 func parseBool(s string) (*bool, error) {
@@ -513,17 +701,6 @@ func parseBool(s string) (*bool, error) {
 	return &b, err
 }
 
-func parseURL(s string) (*string, error) {
-	if s == "" {
-		return nil, nil
-	}
-	_, err := url.ParseRequestURI(s)
-	if err != nil {
-		return nil, err
-	}
-	return &s, nil
-}
-
 func parseInt(s string) (*int, error) {
 	if s == "" {
 		return nil, nil
@@ -540,23 +717,73 @@ func parseInt(s string) (*int, error) {
 
 var cClient *cedexis.Client
 
+// changeLog records the changes of every account's client in a dry run, or is nil
+var changeLog *cedexis.ChangeLog
+
 var cliParser = parser.New(commandSpec)
 
 func main() {
+	debug := flag.Bool("debug", false, "Trace Cedexis API requests (credentials redacted)")
+	rate := flag.Float64("rate", 5, "Maximum Cedexis API requests per second, 0 for unlimited")
+	dryRun := flag.Bool("dry-run", false, "Show changes instead of making them")
+	profile := flag.String("profile", "", "Credentials profile from "+cedexis.DefaultCredentialsFile()+" (default: environment, then 'default' profile)")
+	flag.Parse()
+
 	ctx := context.Background()
 
-	cClient = cedexis.NewClient(ctx, os.Getenv("CEDEXIS_KEY_NAME"), os.Getenv("CEDEXIS_KEY_SECRET"))
+	var opts []cedexis.ClientOption
+	if *rate > 0 {
+		opts = append(opts, cedexis.WithRateLimit(*rate, int(*rate)))
+	}
+	if *dryRun {
+		changeLog = cedexis.NewChangeLog()
+		opts = append(opts, cedexis.WithChangeLog(changeLog))
+	}
+	if *debug {
+		opts = append(opts,
+			cedexis.WithLogger(log.New(os.Stderr, "", log.LstdFlags)),
+			cedexis.WithTrace(cedexis.TraceBodies))
+	}
+
+	// Environment credentials take precedence over the 'default' profile, but are a separate
+	// account.  It is registered first so a profile of the same name is ignored.
+	if _, err := cedexis.NewEnvCredentials().Credentials(ctx); err == nil {
+		addAccount(envAccount, cedexis.NewEnvCredentials())
+	}
+	loadAccounts(opts...)
+
+	account := cedexis.DefaultProfile
+	if *profile != "" {
+		account = *profile
+	} else if _, ok := accountCredentials[envAccount]; ok {
+		account = envAccount
+	}
+	if _, ok := accountCredentials[account]; !ok {
+		addAccount(account, cedexis.NewProfileCredentials(account))
+	}
+
+	if err := useAccount(account); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	err := cClient.Ping()
 	if err != nil {
 		fmt.Println(err)
 	}
 
 	fmt.Println("### Cedexis interactive shell ###")
+	if *dryRun {
+		fmt.Println("Dry run: changes are shown, not made")
+	}
 	p := prompt.New(
 		executor,
 		completer,
 		prompt.OptionTitle("cedexis-cli: interactive shell for cedexis"),
 		prompt.OptionPrefix("> "),
+		prompt.OptionLivePrefix(func() (string, bool) {
+			return activeAccount + "> ", true
+		}),
 	)
 	p.Run()
 }
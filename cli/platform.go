@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"regexp"
+	"time"
 
 	"github.com/ctxkenb/cedexis-golang/cedexis"
 )
@@ -11,8 +12,42 @@ func resetPlatformCache() {
 	platforms = map[cedexis.PlatformType][]*cedexis.PlatformInfo{}
 }
 
+var archetypes *cedexis.ArchetypeCatalogue
+
+// getArchetypeID resolves a community platform from a loose description, e.g. "aws eu-west-1"
+func getArchetypeID(query string, category *cedexis.PlatformCategory) (int, error) {
+	if archetypes == nil {
+		var err error
+		archetypes, err = cClient.GetArchetypeCatalogue()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	a, err := archetypes.Lookup(cedexis.ArchetypeQuery{Text: query, Category: category})
+	if err != nil {
+		return 0, err
+	}
+
+	return a.ID, nil
+}
+
 var platforms = map[cedexis.PlatformType][]*cedexis.PlatformInfo{}
 
+var fusionArchetypes []*cedexis.FusionArchetype
+
+func getFusionArchetypes() ([]*cedexis.FusionArchetype, error) {
+	if fusionArchetypes == nil {
+		var err error
+		fusionArchetypes, err = cClient.GetFusionArchetypes()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fusionArchetypes, nil
+}
+
 func getPlatforms(t cedexis.PlatformType, category *cedexis.PlatformCategory) []*cedexis.PlatformInfo {
 	if platforms[t] == nil {
 		var err error
@@ -28,7 +63,7 @@ func getPlatforms(t cedexis.PlatformType, category *cedexis.PlatformCategory) []
 
 	result := make([]*cedexis.PlatformInfo, 0, len(platforms[t]))
 	for _, p := range platforms[t] {
-		if *p.Category.ID == *category {
+		if p.Category != nil && p.Category.ID != nil && *p.Category.ID == *category {
 			result = append(result, p)
 		}
 	}
@@ -66,7 +101,11 @@ func platformsToTable(platforms []*cedexis.PlatformInfo) *Table {
 		if p.AliasedPlatform != nil && p.AliasedPlatform.Name != nil {
 			archeType = *p.AliasedPlatform.Name
 		}
-		t.Rows[i] = []string{*p.Name, fmt.Sprintf("%d", *p.ID), *p.Category.Name, archeType}
+		category := ""
+		if p.Category != nil && p.Category.Name != nil {
+			category = *p.Category.Name
+		}
+		t.Rows[i] = []string{*p.Name, fmt.Sprintf("%d", *p.ID), category, archeType}
 	}
 
 	return &t
@@ -107,6 +146,64 @@ func createPlatform(name string, shortName string, description string, archeType
 	return nil
 }
 
+func createFusionPlatform(name string, shortName string, description string, archetype string, loadURL string,
+	loadRate time.Duration, tags []string) error {
+
+	p := cedexis.NewFusionPrivatePlatform(shortName, name, description, archetype, loadURL, loadRate, tags)
+
+	_, err := cClient.CreatePrivatePlatform(p)
+	if err != nil {
+		return err
+	}
+
+	resetPlatformCache()
+	return nil
+}
+
+func clonePlatform(source string, target *cedexis.Client, overrides *cedexis.PlatformConfig) error {
+	platformID, err := getPlatformID(source, cedexis.PlatformsTypePrivate, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = cClient.ClonePrivatePlatformTo(target, platformID, overrides)
+	if err != nil {
+		return err
+	}
+
+	resetPlatformCache()
+	return nil
+}
+
+func substitutePlatform(name string, source string, add bool) error {
+	platformID, err := getPlatformID(name, cedexis.PlatformsTypePrivate, nil)
+	if err != nil {
+		return err
+	}
+
+	if add {
+		_, err = cClient.AddSubstitutionSources(platformID, source)
+	} else {
+		_, err = cClient.RemoveSubstitutionSources(platformID, source)
+	}
+	return err
+}
+
+func showSubstitutes() error {
+	g, err := cClient.GetSubstitutionGraph()
+	if err != nil {
+		return err
+	}
+
+	if len(g.Sources) == 0 {
+		fmt.Println("No substitution sources")
+		return nil
+	}
+
+	fmt.Println(g)
+	return g.Validate()
+}
+
 func deletePlatform(name string, t cedexis.PlatformType) error {
 	platformID, err := getPlatformID(name, t, nil)
 	if err != nil {
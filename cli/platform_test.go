@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestGetPlatformsUncategorized(t *testing.T) {
+	defer resetPlatformCache()
+
+	name, other := "uncategorized", "cloud"
+	cat := cedexis.PlatformCategoryCloudComputing
+	platforms[cedexis.PlatformsTypeCommunity] = []*cedexis.PlatformInfo{
+		{Name: &name},
+		{Name: &other, Category: &cedexis.NameID{ID: &cat}},
+	}
+
+	got := getPlatforms(cedexis.PlatformsTypeCommunity, &cat)
+	if len(got) != 1 || *got[0].Name != other {
+		t.Errorf("Got platforms %v, want only the cloud platform", got)
+	}
+}
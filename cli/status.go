@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+const (
+	viewTable    = "table"
+	viewTimeline = "timeline"
+)
+
+var timelineChars = map[cedexis.SonarState]byte{
+	cedexis.SonarStateUnknown: '.',
+	cedexis.SonarStateUp:      '=',
+	cedexis.SonarStateDown:    'X',
+}
+
+func sonarStatusToTable(status *cedexis.SonarStatus) *Table {
+	t := Table{
+		Columns: []string{"Market", "State", "Since", "Last Checked"},
+		Rows:    [][]string{{"All", status.State.String(), formatTime(status.Since), formatTime(status.LastChecked)}},
+	}
+
+	for _, m := range status.Markets {
+		t.Rows = append(t.Rows, []string{string(m.Market), m.State.String(), formatTime(m.Since), formatTime(m.LastChecked)})
+	}
+
+	return &t
+}
+
+// sonarTimeline draws the state of the platform, and from each market, across the history window
+func sonarTimeline(status *cedexis.SonarStatus, h *cedexis.SonarHistory, width int) string {
+	const labelWidth = 4
+
+	buckets := width - labelWidth - 1
+	if buckets < 10 {
+		buckets = 10
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-*s %s .. %s\n", labelWidth, "", formatTime(h.Start), formatTime(h.End))
+
+	row := func(label string, current cedexis.SonarState, market *cedexis.Market) {
+		var transitions []cedexis.SonarTransition
+		for _, t := range h.Transitions {
+			if (t.Market == nil) == (market == nil) && (market == nil || *t.Market == *market) {
+				transitions = append(transitions, t)
+			}
+		}
+
+		state := current
+		if len(transitions) > 0 {
+			state = transitions[0].From
+		}
+
+		line := make([]byte, buckets)
+		span := h.End.Sub(h.Start)
+		next := 0
+		for i := range line {
+			end := h.Start.Add(span * time.Duration(i+1) / time.Duration(buckets))
+			for next < len(transitions) && !transitions[next].Time.After(end) {
+				state = transitions[next].To
+				next++
+			}
+			line[i] = timelineChars[state]
+		}
+
+		fmt.Fprintf(&b, "%-*s %s\n", labelWidth, label, line)
+	}
+
+	row("All", status.State, nil)
+
+	seen := map[cedexis.Market]bool{}
+	for _, m := range status.Markets {
+		market := m.Market
+		seen[market] = true
+		row(string(market), m.State, &market)
+	}
+	for _, t := range h.Transitions {
+		if t.Market != nil && !seen[*t.Market] {
+			market := *t.Market
+			seen[market] = true
+			row(string(market), cedexis.SonarStateUnknown, &market)
+		}
+	}
+
+	fmt.Fprintf(&b, "%-*s %c up  %c down  %c unknown\n", labelWidth, "",
+		timelineChars[cedexis.SonarStateUp], timelineChars[cedexis.SonarStateDown], timelineChars[cedexis.SonarStateUnknown])
+
+	return b.String()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctxkenb/cedexis-golang/cedexis"
+)
+
+func TestSonarTimeline(t *testing.T) {
+	start := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	eu := cedexis.MarketEurope
+
+	status := &cedexis.SonarStatus{
+		State:   cedexis.SonarStateUp,
+		Markets: []cedexis.SonarMarketStatus{{Market: eu, State: cedexis.SonarStateUp}},
+	}
+	h := &cedexis.SonarHistory{
+		Start: start,
+		End:   start.Add(10 * time.Hour),
+		Transitions: []cedexis.SonarTransition{
+			{Time: start.Add(4 * time.Hour), Market: &eu, From: cedexis.SonarStateUp, To: cedexis.SonarStateDown},
+			{Time: start.Add(6 * time.Hour), Market: &eu, From: cedexis.SonarStateDown, To: cedexis.SonarStateUp},
+		},
+	}
+
+	lines := strings.Split(sonarTimeline(status, h, 15), "\n")
+	if len(lines) < 3 || lines[1] != "All  ==========" || lines[2] != "EU   ===XX=====" {
+		t.Errorf("Incorrect timeline:\n%s", strings.Join(lines, "\n"))
+	}
+}
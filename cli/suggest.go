@@ -44,6 +44,60 @@ func suggestSonarMarket(s string) []parser.Suggestion {
 	return parser.FilterHasPrefix(result, s, true)
 }
 
+func suggestSonarMethod(s string) []parser.Suggestion {
+	result := []parser.Suggestion{
+		{Text: cedexis.SonarMethodGet.String(), Description: "GET request"},
+		{Text: cedexis.SonarMethodHead.String(), Description: "HEAD request, no body"},
+		{Text: cedexis.SonarMethodPost.String(), Description: "POST request"},
+	}
+
+	return parser.FilterHasPrefix(result, s, true)
+}
+
+func suggestSonarMatchType(s string) []parser.Suggestion {
+	result := []parser.Suggestion{
+		{Text: cedexis.MatchContains.String(), Description: "Body contains match"},
+		{Text: cedexis.MatchDoesNotContain.String(), Description: "Body does not contain match"},
+		{Text: cedexis.MatchRegex.String(), Description: "Body matches regular expression"},
+	}
+
+	return parser.FilterHasPrefix(result, s, true)
+}
+
+func suggestSonarContentType(s string) []parser.Suggestion {
+	result := []parser.Suggestion{
+		{Text: cedexis.SonarContentTypeJSON.String(), Description: "JSON"},
+		{Text: cedexis.SonarContentTypeForm.String(), Description: "Form"},
+		{Text: cedexis.SonarContentTypeXML.String(), Description: "XML"},
+		{Text: cedexis.SonarContentTypeText.String(), Description: "Plain text"},
+	}
+
+	return parser.FilterHasPrefix(result, s, true)
+}
+
+func suggestFusionArchetypes(s string) []parser.Suggestion {
+	archetypes, err := getFusionArchetypes()
+	if err != nil {
+		return nil
+	}
+
+	result := make([]parser.Suggestion, 0, len(archetypes))
+	for _, a := range archetypes {
+		result = append(result, parser.Suggestion{Text: a.ID, Description: a.Name})
+	}
+
+	return parser.FilterHasPrefix(result, s, true)
+}
+
+func suggestStatusViews(s string) []parser.Suggestion {
+	result := []parser.Suggestion{
+		{Text: viewTable, Description: "Current state by market"},
+		{Text: viewTimeline, Description: "State changes over time"},
+	}
+
+	return parser.FilterHasPrefix(result, s, true)
+}
+
 func suggestAlerts(s string) []parser.Suggestion {
 	alerts, err := getAlerts()
 	if err != nil {
@@ -139,3 +193,14 @@ func suggestZones(s string) []parser.Suggestion {
 
 	return parser.FilterContains(result, s, true)
 }
+
+func suggestAccounts(s string) []parser.Suggestion {
+	names := accountNames()
+
+	result := make([]parser.Suggestion, 0, len(names))
+	for _, name := range names {
+		result = append(result, parser.Suggestion{Text: name})
+	}
+
+	return parser.FilterHasPrefix(result, s, true)
+}